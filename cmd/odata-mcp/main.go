@@ -12,10 +12,14 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/zmcp/odata-mcp/internal/bridge"
 	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
 	"github.com/zmcp/odata-mcp/internal/transport"
 	"github.com/zmcp/odata-mcp/internal/transport/http"
 	"github.com/zmcp/odata-mcp/internal/transport/stdio"
@@ -49,6 +53,10 @@ func init() {
 
 	// Service URL
 	rootCmd.Flags().StringVar(&cfg.ServiceURL, "service", "", "URL of the OData service (overrides positional argument and ODATA_SERVICE_URL env var)")
+	rootCmd.Flags().StringVar(&cfg.ServicesFile, "services", "", "YAML, JSON or TOML file listing several OData services to serve from one MCP server (replaces --service)")
+
+	// Configuration file
+	rootCmd.Flags().StringVar(&cfg.ConfigFile, "config", "", "YAML, JSON or TOML file with bridge options (flags and environment variables take precedence)")
 
 	// Authentication flags (mutually exclusive handled in validation)
 	rootCmd.Flags().StringVarP(&cfg.Username, "user", "u", "", "Username for basic authentication (overrides ODATA_USERNAME env var)")
@@ -56,6 +64,27 @@ func init() {
 	rootCmd.Flags().StringVar(&cfg.Password, "pass", "", "Password for basic authentication (alias for --password)")
 	rootCmd.Flags().StringVar(&cfg.CookieFile, "cookie-file", "", "Path to cookie file in Netscape format")
 	rootCmd.Flags().StringVar(&cfg.CookieString, "cookie-string", "", "Cookie string (key1=val1; key2=val2)")
+	rootCmd.Flags().StringVar(&cfg.BearerToken, "bearer-token", "", "Static bearer token (overrides ODATA_BEARER_TOKEN env var)")
+	rootCmd.Flags().StringVar(&cfg.OAuth2TokenURL, "oauth2-token-url", "", "OAuth2 token endpoint (overrides ODATA_OAUTH2_TOKEN_URL env var)")
+	rootCmd.Flags().StringVar(&cfg.OAuth2ClientID, "oauth2-client-id", "", "OAuth2 client ID (overrides ODATA_OAUTH2_CLIENT_ID env var)")
+	rootCmd.Flags().StringVar(&cfg.OAuth2ClientSecret, "oauth2-client-secret", "", "OAuth2 client secret (overrides ODATA_OAUTH2_CLIENT_SECRET env var)")
+	rootCmd.Flags().StringVar(&cfg.OAuth2Scopes, "oauth2-scopes", "", "Comma-separated OAuth2 scopes (overrides ODATA_OAUTH2_SCOPES env var)")
+	rootCmd.Flags().StringVar(&cfg.OAuth2RefreshToken, "oauth2-refresh-token", "", "OAuth2 refresh token; uses the refresh token grant instead of client credentials (overrides ODATA_OAUTH2_REFRESH_TOKEN env var)")
+	rootCmd.Flags().BoolVar(&cfg.PassThroughAuth, "pass-through-auth", false, "Forward the caller's Authorization header to the OData service (HTTP transports only)")
+	rootCmd.Flags().StringVar(&cfg.PassThroughCookies, "pass-through-cookies", "", "Comma-separated cookie names to forward from the caller (e.g., 'MYSAPSSO2,SAP_SESSIONID_ABC_100'). Implies --pass-through-auth")
+
+	// TLS options
+	rootCmd.Flags().StringVar(&cfg.CAFile, "ca-file", "", "PEM bundle of additional CA certificates to trust")
+	rootCmd.Flags().StringVar(&cfg.ClientCert, "client-cert", "", "PEM or PKCS#12 (.p12, .pfx) client certificate for mutual TLS (may also contain the key)")
+	rootCmd.Flags().StringVar(&cfg.ClientKey, "client-key", "", "PEM private key for --client-cert")
+	rootCmd.Flags().StringVar(&cfg.ClientCertPassword, "client-cert-password", "", "Password of a PKCS#12 --client-cert (overrides ODATA_CLIENT_CERT_PASSWORD env var)")
+	rootCmd.Flags().StringVar(&cfg.TLSMinVersion, "tls-min-version", "", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3 (default: 1.2)")
+	rootCmd.Flags().BoolVar(&cfg.InsecureSkipVerify, "insecure-skip-verify", false, "Skip TLS certificate verification (development only)")
+
+	// Proxy and static request headers
+	rootCmd.Flags().StringVar(&cfg.Proxy, "proxy", "", "Proxy URL for requests to the OData service (default: HTTP_PROXY/HTTPS_PROXY env vars)")
+	rootCmd.Flags().StringVar(&cfg.NoProxy, "no-proxy", "", "Comma-separated hosts, domains or CIDR ranges that bypass --proxy (default: NO_PROXY env var)")
+	rootCmd.Flags().StringArrayVar(&cfg.Headers, "header", nil, "Extra request header 'Name: value', repeatable (e.g., 'SAP-Connectivity-SCC-Location_ID: loc1')")
 
 	// Tool naming options
 	rootCmd.Flags().StringVar(&cfg.ToolPrefix, "tool-prefix", "", "Custom prefix for tool names (use with --no-postfix)")
@@ -67,11 +96,27 @@ func init() {
 	rootCmd.Flags().StringVar(&cfg.Entities, "entities", "", "Comma-separated list of entities to generate tools for (e.g., 'Products,Categories,Orders'). Supports wildcards: 'Product*,Order*'")
 	rootCmd.Flags().StringVar(&cfg.Functions, "functions", "", "Comma-separated list of function imports to generate tools for (e.g., 'GetProducts,CreateOrder'). Supports wildcards: 'Get*,Create*'")
 
+	// Write protection
+	rootCmd.Flags().BoolVar(&cfg.ReadOnly, "read-only", false, "Only generate read tools: no create, update, delete or POST function tools")
+	rootCmd.Flags().BoolVar(&cfg.NoPostFunctions, "no-post-functions", false, "Do not generate tools for function imports invoked with POST")
+	rootCmd.Flags().StringArrayVar(&cfg.EntityOperations, "entity-ops", nil, "Per-entity operation rule, repeatable: 'Orders:get,filter' allows only these, 'Products:!delete' removes one. Supports wildcards")
+	rootCmd.Flags().BoolVar(&cfg.ConfirmWrites, "confirm-writes", false, "Require two-phase confirmation for update, delete and POST function tools: the first call returns a preview and a one-time token")
+	rootCmd.Flags().DurationVar(&cfg.ConfirmTTL, "confirm-ttl", constants.DefaultConfirmationTTL, "Lifetime of confirmation tokens issued by --confirm-writes")
+
 	// Output and debugging options
 	rootCmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output to stderr")
 	rootCmd.Flags().BoolVar(&cfg.Debug, "debug", false, "Alias for --verbose")
 	rootCmd.Flags().BoolVar(&cfg.SortTools, "sort-tools", true, "Sort tools alphabetically in the output")
 	rootCmd.Flags().BoolVar(&cfg.Trace, "trace", false, "Initialize MCP service and print all tools and parameters, then exit (useful for debugging)")
+	rootCmd.Flags().BoolVar(&cfg.ValidateArgs, "validate-args", false, "Reject tool calls whose arguments do not match the tool input schema (types, unknown properties, lengths, ranges) before calling the service")
+	rootCmd.Flags().BoolVar(&cfg.DryRun, "dry-run", false, "Return the HTTP request of create, update, delete and function tools (credentials redacted) instead of sending it")
+
+	// Metadata cache and local metadata
+	rootCmd.Flags().StringVar(&cfg.MetadataCacheDir, "metadata-cache-dir", "", "Cache $metadata in this directory, revalidate it with ETag/Last-Modified and fall back to it when the service is unreachable")
+	rootCmd.Flags().DurationVar(&cfg.MetadataCacheTTL, "metadata-cache-ttl", 0, "Use cached metadata younger than this without revalidation (default: always revalidate)")
+	rootCmd.Flags().StringVar(&cfg.MetadataFile, "metadata-file", "", "Load the EDMX metadata from a local file instead of the service")
+	rootCmd.Flags().DurationVar(&cfg.MetadataRefresh, "metadata-refresh", 0, "Reload the metadata at this interval and update the tool list (default: disabled)")
+	rootCmd.Flags().BoolVar(&cfg.ReloadTool, "reload-tool", false, "Add a tool that reloads the metadata on demand")
 	
 	// Response enhancement options
 	rootCmd.Flags().BoolVar(&cfg.PaginationHints, "pagination-hints", false, "Add pagination support with suggested_next_call and has_more indicators")
@@ -85,8 +130,9 @@ func init() {
 	rootCmd.Flags().IntVar(&cfg.MaxItems, "max-items", 100, "Maximum number of items in response (default: 100)")
 	
 	// Transport options
-	rootCmd.Flags().String("transport", "stdio", "Transport type: 'stdio' or 'http' (SSE)")
-	rootCmd.Flags().String("http-addr", ":8080", "HTTP server address (used with --transport http)")
+	rootCmd.Flags().String("transport", "stdio", "Transport type: 'stdio', 'http' (SSE) or 'streamable-http'")
+	rootCmd.Flags().String("http-addr", ":8080", "HTTP server address (used with --transport http or streamable-http)")
+	rootCmd.Flags().String("allowed-origins", "", "Comma-separated browser origins allowed besides localhost (used with --transport streamable-http)")
 
 	// Bind flags to viper for environment variable support
 	viper.BindPFlag("service", rootCmd.Flags().Lookup("service"))
@@ -100,7 +146,21 @@ func init() {
 	viper.SetEnvPrefix("ODATA")
 }
 
+// mcpBridge is implemented by the single- and multi-service bridges
+type mcpBridge interface {
+	GetServer() *mcp.Server
+	Run() error
+	Stop()
+}
+
 func runBridge(cmd *cobra.Command, args []string) error {
+	// Load the configuration file first so flags and environment variables override it
+	if cfg.ConfigFile != "" {
+		if err := applyConfigFile(cmd, cfg.ConfigFile); err != nil {
+			return err
+		}
+	}
+
 	// Handle --debug as alias for --verbose
 	if cfg.Debug {
 		cfg.Verbose = true
@@ -120,66 +180,85 @@ func runBridge(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Determine service URL with priority: --service flag > positional arg > env vars
-	if cfg.ServiceURL == "" && len(args) > 0 {
-		cfg.ServiceURL = args[0]
+	// Parse static request headers
+	if len(cfg.Headers) > 0 {
+		headers, err := parseHeaders(cfg.Headers)
+		if err != nil {
+			return err
+		}
+		cfg.ExtraHeaders = headers
 		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Using OData service URL from positional argument.\n")
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Adding %d extra headers to every request\n", len(headers))
 		}
 	}
 
-	if cfg.ServiceURL == "" {
-		cfg.ServiceURL = viper.GetString("URL")
-		if cfg.ServiceURL == "" {
-			cfg.ServiceURL = viper.GetString("SERVICE_URL")
-		}
-		if cfg.ServiceURL != "" && cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Using ODATA_URL from environment.\n")
-		}
+	if cfg.ClientCertPassword == "" {
+		cfg.ClientCertPassword = viper.GetString("CLIENT_CERT_PASSWORD")
 	}
-
-	if cfg.ServiceURL == "" {
-		return fmt.Errorf("OData service URL not provided. Use --service flag, positional argument, or ODATA_URL environment variable")
+	if cfg.InsecureSkipVerify {
+		fmt.Fprintf(os.Stderr, "WARNING: TLS certificate verification is disabled (--insecure-skip-verify). Do not use this in production.\n")
 	}
 
-	// Validate and process authentication
-	if err := processAuthentication(cfg); err != nil {
-		return err
+	// Pass-through authentication takes credentials from incoming HTTP requests
+	transportType, _ := cmd.Flags().GetString("transport")
+	if cfg.PassThroughCookies != "" {
+		cfg.PassThroughAuth = true
+		cfg.PassThroughCookieNames = parseCommaSeparated(cfg.PassThroughCookies)
 	}
-
-	// Parse entity and function filters
-	if cfg.Entities != "" {
-		cfg.AllowedEntities = parseCommaSeparated(cfg.Entities)
+	if cfg.PassThroughAuth {
+		if transportType != "http" && transportType != "sse" && transportType != "streamable-http" {
+			return fmt.Errorf("--pass-through-auth requires --transport http or streamable-http")
+		}
 		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Filtering tools to only these entities: %v\n", cfg.AllowedEntities)
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Forwarding end-user Authorization header and cookies %v to the OData service\n", cfg.PassThroughCookieNames)
 		}
 	}
 
-	if cfg.Functions != "" {
-		cfg.AllowedFunctions = parseCommaSeparated(cfg.Functions)
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Filtering tools to only these functions: %v\n", cfg.AllowedFunctions)
+	// A services file replaces the single service URL, authentication and filters
+	var services []*config.Config
+	if cfg.ServicesFile != "" {
+		if cfg.ServiceURL != "" || len(args) > 0 {
+			return fmt.Errorf("--services cannot be combined with a single service URL")
 		}
+		var err error
+		services, err = loadServices(cfg.ServicesFile, cfg)
+		if err != nil {
+			return err
+		}
+	} else if err := configureService(cfg, args); err != nil {
+		return err
 	}
 
 	// Set up signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
 
 	// Create and initialize bridge
-	odataBridge, err := bridge.NewODataMCPBridge(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to create OData MCP bridge: %w", err)
-	}
-
-	// Handle trace mode
-	if cfg.Trace {
-		return printTraceInfo(odataBridge)
+	var odataBridge mcpBridge
+	if services != nil {
+		multiBridge, err := bridge.NewMultiServiceBridge(services)
+		if err != nil {
+			return fmt.Errorf("failed to create OData MCP bridge: %w", err)
+		}
+		if cfg.Trace {
+			return printMultiTraceInfo(multiBridge)
+		}
+		odataBridge = multiBridge
+	} else {
+		singleBridge, err := bridge.NewODataMCPBridge(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create OData MCP bridge: %w", err)
+		}
+		// Handle trace mode
+		if cfg.Trace {
+			return printTraceInfo(singleBridge)
+		}
+		odataBridge = singleBridge
 	}
 	
 	// Set up transport based on flag
-	transportType, _ := cmd.Flags().GetString("transport")
-	
 	// Get the MCP server from the bridge
 	mcpServer := odataBridge.GetServer()
 	if mcpServer == nil {
@@ -198,7 +277,24 @@ func runBridge(cmd *cobra.Command, args []string) error {
 		if cfg.Verbose {
 			fmt.Fprintf(os.Stderr, "[VERBOSE] Starting HTTP/SSE transport on %s\n", httpAddr)
 		}
-		trans = http.NewSSE(httpAddr, handler)
+		sseTrans := http.NewSSE(httpAddr, handler)
+		if cfg.PassThroughAuth {
+			sseTrans.SetPassThroughAuth(cfg.PassThroughCookieNames)
+		}
+		trans = sseTrans
+	case "streamable-http":
+		httpAddr, _ := cmd.Flags().GetString("http-addr")
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Starting Streamable HTTP transport on %s%s\n", httpAddr, http.DefaultMCPEndpoint)
+		}
+		streamableTrans := http.NewStreamableHTTP(httpAddr, handler)
+		if origins, _ := cmd.Flags().GetString("allowed-origins"); origins != "" {
+			streamableTrans.SetAllowedOrigins(parseCommaSeparated(origins))
+		}
+		if cfg.PassThroughAuth {
+			streamableTrans.SetPassThroughAuth(cfg.PassThroughCookieNames)
+		}
+		trans = streamableTrans
 	case "stdio":
 		fallthrough
 	default:
@@ -217,18 +313,245 @@ func runBridge(cmd *cobra.Command, args []string) error {
 		errChan <- odataBridge.Run()
 	}()
 
-	// Wait for signal or error
-	select {
-	case sig := <-sigChan:
-		fmt.Fprintf(os.Stderr, "\n%s received, shutting down server...\n", sig)
-		odataBridge.Stop()
-		return nil
-	case err := <-errChan:
+	// Wait for signal or error, reloading the metadata on SIGHUP
+	for {
+		select {
+		case sig := <-sigChan:
+			fmt.Fprintf(os.Stderr, "\n%s received, shutting down server...\n", sig)
+			odataBridge.Stop()
+			return nil
+		case <-reloadChan:
+			reloadMetadata(odataBridge)
+		case err := <-errChan:
+			return err
+		}
+	}
+}
+
+// reloadMetadata reloads the metadata of a bridge and reports the tool changes on stderr
+func reloadMetadata(odataBridge mcpBridge) {
+	ctx := context.Background()
+	var summaries map[string]*models.ReloadSummary
+	var err error
+	switch b := odataBridge.(type) {
+	case *bridge.ODataMCPBridge:
+		var summary *models.ReloadSummary
+		if summary, err = b.Reload(ctx); err == nil {
+			summaries = map[string]*models.ReloadSummary{cfg.ServiceURL: summary}
+		}
+	case *bridge.MultiServiceBridge:
+		summaries, err = b.Reload(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: metadata reload failed: %v\n", err)
+	}
+	for name, summary := range summaries {
+		fmt.Fprintf(os.Stderr, "Reloaded metadata of %s: %d tools added, %d changed, %d removed\n",
+			name, len(summary.AddedTools), len(summary.ChangedTools), len(summary.RemovedTools))
+	}
+}
+
+// applyConfigFile loads options from a configuration file with the precedence
+// flags > environment > file. File values for the service URL and credentials
+// are dropped when the matching ODATA_* environment variables are set, and
+// flags given on the command line are applied again on top of the file.
+func applyConfigFile(cmd *cobra.Command, path string) error {
+	// Flags share their variables with cfg, so remember them before the file overwrites them
+	flagValues := make(map[*pflag.Flag][]string)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			flagValues[f] = slice.GetSlice()
+		} else {
+			flagValues[f] = []string{f.Value.String()}
+		}
+	})
+
+	if err := cfg.LoadFile(path); err != nil {
+		return err
+	}
+
+	if viper.GetString("URL") != "" || viper.GetString("SERVICE_URL") != "" {
+		cfg.ServiceURL = ""
+	}
+	if viper.GetString("USER") != "" || viper.GetString("USERNAME") != "" {
+		cfg.Username = ""
+	}
+	if viper.GetString("PASS") != "" || viper.GetString("PASSWORD") != "" {
+		cfg.Password = ""
+	}
+	// Token and cookie variables select another authentication method
+	for _, key := range []string{"BEARER_TOKEN", "OAUTH2_TOKEN_URL", "COOKIE_FILE", "COOKIE_STRING"} {
+		if viper.GetString(key) != "" {
+			cfg.Username, cfg.Password = "", ""
+			cfg.CookieFile, cfg.CookieString = "", ""
+			cfg.BearerToken = ""
+			cfg.OAuth2TokenURL, cfg.OAuth2ClientID, cfg.OAuth2ClientSecret = "", "", ""
+			cfg.OAuth2Scopes, cfg.OAuth2RefreshToken = "", ""
+			break
+		}
+	}
+
+	for f, values := range flagValues {
+		var err error
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			err = slice.Replace(values)
+		} else {
+			err = f.Value.Set(values[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply --%s over config file: %w", f.Name, err)
+		}
+	}
+
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Loaded configuration from %s\n", path)
+	}
+	return nil
+}
+
+// configureService resolves the service URL, authentication and filters of a single-service bridge
+func configureService(cfg *config.Config, args []string) error {
+	// Determine service URL with priority: --service flag > positional arg > env vars
+	if cfg.ServiceURL == "" && len(args) > 0 {
+		cfg.ServiceURL = args[0]
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Using OData service URL from positional argument.\n")
+		}
+	}
+
+	if cfg.ServiceURL == "" {
+		cfg.ServiceURL = viper.GetString("URL")
+		if cfg.ServiceURL == "" {
+			cfg.ServiceURL = viper.GetString("SERVICE_URL")
+		}
+		if cfg.ServiceURL != "" && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Using ODATA_URL from environment.\n")
+		}
+	}
+
+	if cfg.ServiceURL == "" {
+		return fmt.Errorf("OData service URL not provided. Use --service flag, positional argument, or ODATA_URL environment variable")
+	}
+
+	// Validate and process authentication
+	if err := processAuthentication(cfg, true); err != nil {
 		return err
 	}
+
+	return parseFilters(cfg)
+}
+
+// loadServices reads the services file used in multi-service mode. Each entry
+// starts from the global configuration without its service URL, credentials,
+// filters and tool naming, then applies its own settings using the same keys
+// as the Config mapstructure tags (service_url, username, entities, tool_postfix, ...).
+func loadServices(path string, base *config.Config) ([]*config.Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read services file: %w", err)
+	}
+
+	var entries []map[string]interface{}
+	if err := v.UnmarshalKey("services", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse services file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("services file %s does not list any services", path)
+	}
+
+	services := make([]*config.Config, 0, len(entries))
+	for i, entry := range entries {
+		svc := *base
+		svc.ServicesFile = ""
+		svc.ServiceURL, svc.Name = "", ""
+		svc.Username, svc.Password = "", ""
+		svc.CookieFile, svc.CookieString, svc.Cookies = "", "", nil
+		svc.BearerToken = ""
+		svc.OAuth2TokenURL, svc.OAuth2ClientID, svc.OAuth2ClientSecret = "", "", ""
+		svc.OAuth2Scopes, svc.OAuth2RefreshToken = "", ""
+		svc.Entities, svc.Functions = "", ""
+		svc.AllowedEntities, svc.AllowedFunctions = nil, nil
+		svc.ToolPrefix, svc.ToolPostfix = "", ""
+		svc.MetadataFile = "" // A metadata file describes a single service
+
+		sv := viper.New()
+		if err := sv.MergeConfigMap(entry); err != nil {
+			return nil, fmt.Errorf("service #%d: %w", i+1, err)
+		}
+		if err := sv.Unmarshal(&svc); err != nil {
+			return nil, fmt.Errorf("service #%d: %w", i+1, err)
+		}
+
+		label := svc.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if svc.ServiceURL == "" {
+			return nil, fmt.Errorf("service %s: service_url is required", label)
+		}
+		if err := processAuthentication(&svc, false); err != nil {
+			return nil, fmt.Errorf("service %s: %w", label, err)
+		}
+		if sv.IsSet("headers") {
+			headers, err := parseHeaders(svc.Headers)
+			if err != nil {
+				return nil, fmt.Errorf("service %s: %w", label, err)
+			}
+			svc.ExtraHeaders = headers
+		}
+		if err := parseFilters(&svc); err != nil {
+			return nil, fmt.Errorf("service %s: %w", label, err)
+		}
+
+		if svc.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Service %s: %s\n", label, svc.ServiceURL)
+		}
+		services = append(services, &svc)
+	}
+
+	return services, nil
 }
 
-func processAuthentication(cfg *config.Config) error {
+// parseFilters parses the entity and function filters and operation rules of a service
+func parseFilters(cfg *config.Config) error {
+	if cfg.Entities != "" {
+		cfg.AllowedEntities = parseCommaSeparated(cfg.Entities)
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Filtering tools to only these entities: %v\n", cfg.AllowedEntities)
+		}
+	}
+
+	if cfg.Functions != "" {
+		cfg.AllowedFunctions = parseCommaSeparated(cfg.Functions)
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Filtering tools to only these functions: %v\n", cfg.AllowedFunctions)
+		}
+	}
+
+	if len(cfg.EntityOperations) > 0 {
+		rules, err := config.ParseOperationRules(cfg.EntityOperations)
+		if err != nil {
+			return err
+		}
+		cfg.OperationRules = rules
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Applying entity operation rules: %v\n", cfg.EntityOperations)
+		}
+	}
+
+	if cfg.ReadOnly && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Read-only mode: create, update, delete and POST function tools are disabled\n")
+	}
+	if cfg.DryRun && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Dry-run mode: create, update, delete and function tools only return their requests\n")
+	}
+	return nil
+}
+
+// processAuthentication validates and loads the configured credentials. With
+// fromEnv, missing credentials are taken from ODATA_* environment variables.
+func processAuthentication(cfg *config.Config, fromEnv bool) error {
 	// Check for mutually exclusive authentication options
 	authMethods := 0
 	if cfg.CookieFile != "" {
@@ -240,6 +563,12 @@ func processAuthentication(cfg *config.Config) error {
 	if cfg.Username != "" {
 		authMethods++
 	}
+	if cfg.BearerToken != "" {
+		authMethods++
+	}
+	if cfg.OAuth2TokenURL != "" || cfg.OAuth2ClientID != "" || cfg.OAuth2RefreshToken != "" {
+		authMethods++
+	}
 
 	if authMethods > 1 {
 		return fmt.Errorf("only one authentication method can be used at a time")
@@ -271,7 +600,15 @@ func processAuthentication(cfg *config.Config) error {
 		if cfg.Verbose {
 			fmt.Fprintf(os.Stderr, "[VERBOSE] Parsed %d cookies from string\n", len(cookies))
 		}
-	} else {
+	} else if cfg.BearerToken != "" {
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Using static bearer token authentication\n")
+		}
+	} else if cfg.OAuth2TokenURL != "" || cfg.OAuth2ClientID != "" || cfg.OAuth2RefreshToken != "" {
+		if err := validateOAuth2(cfg); err != nil {
+			return err
+		}
+	} else if fromEnv {
 		// Handle basic authentication from environment if not provided via flags
 		if cfg.Username == "" {
 			cfg.Username = viper.GetString("USER")
@@ -287,8 +624,27 @@ func processAuthentication(cfg *config.Config) error {
 			}
 		}
 
-		// Check for cookie environment variables if no auth is configured
+		// Check for bearer token and OAuth2 environment variables
 		if cfg.Username == "" {
+			cfg.BearerToken = viper.GetString("BEARER_TOKEN")
+			if cfg.BearerToken == "" {
+				cfg.OAuth2TokenURL = viper.GetString("OAUTH2_TOKEN_URL")
+				cfg.OAuth2ClientID = viper.GetString("OAUTH2_CLIENT_ID")
+				cfg.OAuth2ClientSecret = viper.GetString("OAUTH2_CLIENT_SECRET")
+				cfg.OAuth2Scopes = viper.GetString("OAUTH2_SCOPES")
+				cfg.OAuth2RefreshToken = viper.GetString("OAUTH2_REFRESH_TOKEN")
+				if cfg.OAuth2TokenURL != "" {
+					if err := validateOAuth2(cfg); err != nil {
+						return err
+					}
+				}
+			} else if cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "[VERBOSE] Using bearer token from environment ODATA_BEARER_TOKEN\n")
+			}
+		}
+
+		// Check for cookie environment variables if no auth is configured
+		if cfg.Username == "" && !cfg.HasBearerToken() && !cfg.HasOAuth2() {
 			envCookieFile := viper.GetString("COOKIE_FILE")
 			envCookieString := viper.GetString("COOKIE_STRING")
 
@@ -318,7 +674,7 @@ func processAuthentication(cfg *config.Config) error {
 			if cfg.Verbose {
 				fmt.Fprintf(os.Stderr, "[VERBOSE] Using basic authentication for user: %s\n", cfg.Username)
 			}
-		} else if cfg.Verbose && len(cfg.Cookies) == 0 {
+		} else if cfg.Verbose && len(cfg.Cookies) == 0 && !cfg.HasBearerToken() && !cfg.HasOAuth2() {
 			fmt.Fprintf(os.Stderr, "[VERBOSE] No authentication provided or configured. Attempting anonymous access.\n")
 		}
 	}
@@ -326,6 +682,28 @@ func processAuthentication(cfg *config.Config) error {
 	return nil
 }
 
+// validateOAuth2 checks that the OAuth2 settings are complete enough to request tokens
+func validateOAuth2(cfg *config.Config) error {
+	if cfg.OAuth2TokenURL == "" {
+		return fmt.Errorf("--oauth2-token-url is required for OAuth2 authentication")
+	}
+	if cfg.OAuth2ClientID == "" {
+		return fmt.Errorf("--oauth2-client-id is required for OAuth2 authentication")
+	}
+	if cfg.OAuth2RefreshToken == "" && cfg.OAuth2ClientSecret == "" {
+		return fmt.Errorf("--oauth2-client-secret is required for the client credentials grant")
+	}
+
+	if cfg.Verbose {
+		grant := "client credentials"
+		if cfg.OAuth2RefreshToken != "" {
+			grant = "refresh token"
+		}
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Using OAuth2 %s grant with token endpoint: %s\n", grant, cfg.OAuth2TokenURL)
+	}
+	return nil
+}
+
 func loadCookiesFromFile(cookieFile string) (map[string]string, error) {
 	cookies := make(map[string]string)
 
@@ -375,6 +753,20 @@ func parseCookieString(cookieString string) map[string]string {
 	return cookies
 }
 
+// parseHeaders parses "Name: value" header definitions
+func parseHeaders(definitions []string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, def := range definitions {
+		name, value, found := strings.Cut(def, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: value'", def)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
 func parseCommaSeparated(input string) []string {
 	var result []string
 	for _, item := range strings.Split(input, ",") {
@@ -413,6 +805,31 @@ func printTraceInfo(bridge *bridge.ODataMCPBridge) error {
 	return nil
 }
 
+func printMultiTraceInfo(multiBridge *bridge.MultiServiceBridge) error {
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println("🔍 OData MCP Bridge Trace Information")
+	fmt.Println(strings.Repeat("=", 80))
+
+	infos, err := multiBridge.GetTraceInfo()
+	if err != nil {
+		return fmt.Errorf("failed to get trace info: %w", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"services": infos}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace info: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	fmt.Println("\n" + strings.Repeat("=", 80))
+	fmt.Printf("✅ Trace complete - %d services initialized successfully but not started\n", len(infos))
+	fmt.Println("💡 Use without --trace to start the actual MCP server")
+	fmt.Println(strings.Repeat("=", 80))
+
+	return nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "\n--- FATAL ERROR ---\n")
@@ -0,0 +1,197 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// annotateFilterProperties adds the filterable, sortable and required filter
+// properties of an entity set to the $filter and $orderby descriptions of a
+// filter tool. Properties are only listed when the service restricts them.
+// Reports whether the service requires a filter.
+func (b *ODataMCPBridge) annotateFilterProperties(entitySetName string, entitySet *models.EntitySet, entityType *models.EntityType, properties map[string]interface{}) bool {
+	if !entitySet.Filterable {
+		return false
+	}
+
+	var filterable, sortable []string
+	restrictedFilter, restrictedSort := false, false
+	for _, prop := range entityType.Properties {
+		if b.isHiddenField(entitySetName, prop) {
+			continue
+		}
+		if isFilterable(entitySet, prop) {
+			filterable = append(filterable, prop.Name)
+		} else {
+			restrictedFilter = true
+		}
+		if prop.Sortable {
+			sortable = append(sortable, prop.Name)
+		} else {
+			restrictedSort = true
+		}
+	}
+	required := requiredFilterProperties(entitySet, entityType)
+
+	if filter, ok := properties["$filter"].(map[string]interface{}); ok {
+		description := filter["description"].(string)
+		if restrictedFilter {
+			description = fmt.Sprintf("%s. Filterable properties: %s", description, strings.Join(filterable, ", "))
+		}
+		if len(required) > 0 {
+			description = fmt.Sprintf("%s. Required filter properties: %s", description, strings.Join(required, ", "))
+		} else if entitySet.RequiresFilter {
+			description = fmt.Sprintf("%s. Required by the service", description)
+		}
+		filter["description"] = description
+	}
+	if orderby, ok := properties["$orderby"].(map[string]interface{}); ok && restrictedSort {
+		orderby["description"] = fmt.Sprintf("%s. Sortable properties: %s", orderby["description"], strings.Join(sortable, ", "))
+	}
+	return entitySet.RequiresFilter || len(required) > 0
+}
+
+// restrictQueryOptions removes the query options an entity set does not
+// support (Capabilities FilterRestrictions, CountRestrictions, TopSupported)
+func restrictQueryOptions(entitySet *models.EntitySet, properties map[string]interface{}) {
+	if !entitySet.Filterable {
+		delete(properties, "$filter")
+	}
+	if !entitySet.Countable {
+		delete(properties, "$count")
+	}
+	if !entitySet.TopSupported {
+		delete(properties, "$top")
+	}
+}
+
+// isFilterable reports whether a property may be used in a filter on an entity set
+func isFilterable(entitySet *models.EntitySet, prop *models.EntityProperty) bool {
+	if !prop.Filterable {
+		return false
+	}
+	for _, name := range entitySet.NonFilterableProperties {
+		if name == prop.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// requiredFilterProperties returns the properties of an entity set marked
+// sap:required-in-filter or listed in the RequiredProperties of its
+// FilterRestrictions
+func requiredFilterProperties(entitySet *models.EntitySet, entityType *models.EntityType) []string {
+	required := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, prop := range entityType.Properties {
+		if prop.RequiredInFilter {
+			required = append(required, prop.Name)
+			seen[prop.Name] = true
+		}
+	}
+	for _, name := range entitySet.RequiredFilterProperties {
+		if !seen[name] {
+			required = append(required, name)
+			seen[name] = true
+		}
+	}
+	return required
+}
+
+// checkRequiredFilters rejects a query without the $filter the service
+// requires, or whose $filter does not mention every required property, before
+// the service is called
+func (b *ODataMCPBridge) checkRequiredFilters(entitySetName string, options map[string]string) error {
+	meta := b.metadata.Load()
+	entitySet, ok := meta.EntitySets[entitySetName]
+	if !ok {
+		return nil
+	}
+	entityType, ok := meta.EntityTypes[entitySet.EntityType]
+	if !ok {
+		return nil
+	}
+
+	filter := options[constants.QueryFilter]
+	if entitySet.RequiresFilter && strings.TrimSpace(filter) == "" {
+		return fmt.Errorf("%s requires a $filter", entitySetName)
+	}
+
+	missing := make([]string, 0)
+	for _, name := range requiredFilterProperties(entitySet, entityType) {
+		if !containsWord(filter, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s requires a $filter on %s", entitySetName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// containsWord reports whether word occurs in s with no letter, digit or
+// underscore directly before or after it
+func containsWord(s, word string) bool {
+	for offset := 0; offset+len(word) <= len(s); {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// propertyNotes describes the SAP text property and display format of a property
+func propertyNotes(prop *models.EntityProperty, description string) string {
+	notes := make([]string, 0, 2)
+	if prop.Text != "" {
+		notes = append(notes, fmt.Sprintf("text in %s", prop.Text))
+	}
+	if prop.DisplayFormat != "" {
+		notes = append(notes, fmt.Sprintf("display format %s", prop.DisplayFormat))
+	}
+	if len(notes) == 0 {
+		return description
+	}
+	return fmt.Sprintf("%s (%s)", description, strings.Join(notes, ", "))
+}
+
+// annotatedDescription returns the description annotated on an entity set or
+// singleton (Core.Description), or else on its entity type
+func (b *ODataMCPBridge) annotatedDescription(name string) string {
+	meta := b.metadata.Load()
+	if meta == nil {
+		return ""
+	}
+
+	var description *string
+	var typeName string
+	if entitySet, ok := meta.EntitySets[name]; ok {
+		description, typeName = entitySet.Description, entitySet.EntityType
+	} else if singleton, ok := meta.Singletons[name]; ok {
+		description, typeName = singleton.Description, singleton.EntityType
+	}
+	if description == nil {
+		if entityType, ok := meta.EntityTypes[typeName]; ok {
+			description = entityType.Description
+		}
+	}
+	if description == nil {
+		return ""
+	}
+	return *description
+}
@@ -0,0 +1,364 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+	"github.com/zmcp/odata-mcp/internal/utils"
+)
+
+// batchReferencePattern matches Content-ID references to a navigation property of an earlier create
+var batchReferencePattern = regexp.MustCompile(`^\$(\d+)/([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// generateBatchTool creates a tool that runs several entity operations in one $batch request
+func (b *ODataMCPBridge) generateBatchTool() {
+	toolName := b.formatToolName("odata_batch", "")
+
+	description := "Run several get/filter/create/update/delete operations in a single OData $batch request. " +
+		"With atomic=true (default) all writes succeed or fail together. " +
+		"A create can target a navigation property of an earlier create with entity_set \"$<n>/<NavProperty>\", where n is the 1-based position of that operation (atomic batches only)."
+	operations := []string{constants.OpGet, constants.OpFilter, constants.OpCreate, constants.OpUpdate, constants.OpDelete}
+
+	// Read-only mode limits the batch to read operations
+	if b.config.ReadOnly {
+		description = "Run several get/filter operations in a single OData $batch request."
+		operations = operations[:2]
+	}
+
+	if b.config.ConfirmWrites && !b.config.ReadOnly {
+		description += " Batches with update or delete operations require confirmation: the first call only returns a preview and a confirmation token."
+	}
+
+	properties := map[string]interface{}{
+		"operations": map[string]interface{}{
+			"type":        "array",
+			"description": fmt.Sprintf("Operations to execute in order (maximum %d)", constants.DefaultMaxBatchOperations),
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"operation": map[string]interface{}{
+						"type":        "string",
+						"description": "Operation to perform",
+						"enum":        operations,
+					},
+					"entity_set": map[string]interface{}{
+						"type":        "string",
+						"description": "Target entity set",
+					},
+					"key": map[string]interface{}{
+						"type":        "object",
+						"description": "Key property values (get, update, delete)",
+					},
+					"data": map[string]interface{}{
+						"type":        "object",
+						"description": "Entity properties (create, update)",
+					},
+					"options": map[string]interface{}{
+						"type":        "object",
+						"description": "OData query options such as $filter, $select, $expand, $top (get, filter)",
+					},
+					"method": map[string]interface{}{
+						"type":        "string",
+						"description": "HTTP method for update (PUT, PATCH, or MERGE)",
+						"enum":        []string{"PUT", "PATCH", "MERGE"},
+					},
+				},
+				"required": []string{"operation", "entity_set"},
+			},
+		},
+		"atomic": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Apply all write operations in a single changeset",
+			"default":     true,
+		},
+	}
+	b.addDryRunParameter(properties)
+	if b.config.ConfirmWrites {
+		b.addConfirmationParameter(properties)
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   []string{"operations"},
+		},
+	}
+
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleBatch(ctx, args)
+	})
+	if b.config.ConfirmWrites {
+		handler = b.withConfirmation(toolName, b.previewBatch, handler)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		Operation:   constants.OpBatch,
+	}
+}
+
+func (b *ODataMCPBridge) handleBatch(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	rawOps, ok := args["operations"].([]interface{})
+	if !ok || len(rawOps) == 0 {
+		return nil, fmt.Errorf("missing required parameter: operations")
+	}
+	if len(rawOps) > constants.DefaultMaxBatchOperations {
+		return nil, fmt.Errorf("batch contains %d operations, maximum is %d", len(rawOps), constants.DefaultMaxBatchOperations)
+	}
+
+	atomic := batchAtomic(args)
+
+	ops := make([]*models.BatchOperation, 0, len(rawOps))
+	entitySets := make([]string, 0, len(rawOps))
+	created := batchCreates(atomic)
+	for i, raw := range rawOps {
+		opArgs, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("operation %d: expected an object", i+1)
+		}
+		op, entitySetName, err := b.buildBatchOperation(opArgs, created)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i+1, err)
+		}
+		if op.Method == constants.POST && created != nil {
+			created[i+1] = entitySetName
+		}
+		ops = append(ops, op)
+		entitySets = append(entitySets, entitySetName)
+	}
+
+	results, err := b.clientFor(ctx).ExecuteBatch(ctx, ops, atomic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute batch: %w", err)
+	}
+
+	failed := 0
+	for i, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		if b.config.LegacyDates {
+			result.Value = b.convertLegacyDates(result.Value)
+		}
+		if !b.config.ResponseMetadata {
+			result.Value = b.stripMetadata(result.Value)
+		}
+		if i < len(entitySets) {
+			b.hideFieldsIn(entitySets[i], result.Value)
+		}
+	}
+
+	response, err := json.Marshal(map[string]interface{}{
+		"atomic":  atomic,
+		"total":   len(results),
+		"failed":  failed,
+		"results": results,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(response), nil
+}
+
+// batchAtomic reports whether the writes of a batch tool call go in one changeset
+func batchAtomic(args map[string]interface{}) bool {
+	if val, ok := args["atomic"].(bool); ok {
+		return val
+	}
+	return true
+}
+
+// batchCreates returns the map that tracks the position of each create and the
+// entity set of the new entity. Content-IDs only resolve within a changeset, so
+// non-atomic batches, which put every write in its own, get none.
+func batchCreates(atomic bool) map[int]string {
+	if !atomic {
+		return nil
+	}
+	return make(map[int]string)
+}
+
+// buildBatchOperation validates a single batch tool operation and converts it
+// to a client operation. It also returns the entity set the operation targets,
+// which for a Content-ID reference is the target of its navigation property.
+func (b *ODataMCPBridge) buildBatchOperation(args map[string]interface{}, created map[int]string) (*models.BatchOperation, string, error) {
+	meta := b.metadata.Load()
+	operation, _ := args["operation"].(string)
+	path, _ := args["entity_set"].(string)
+	if path == "" {
+		return nil, "", fmt.Errorf("missing required field: entity_set")
+	}
+
+	// Content-ID references ($1/Items) can only be used to create related entities
+	entitySetName := path
+	if strings.HasPrefix(path, "$") {
+		if operation != constants.OpCreate {
+			return nil, "", fmt.Errorf("entity_set references are only supported for create")
+		}
+		if created == nil {
+			return nil, "", fmt.Errorf("entity_set references require atomic=true")
+		}
+		target, err := b.resolveBatchReference(path, created)
+		if err != nil {
+			return nil, "", err
+		}
+		entitySetName = target
+	}
+
+	entitySet, exists := meta.EntitySets[entitySetName]
+	if !exists || !b.shouldIncludeEntity(entitySetName) {
+		return nil, "", fmt.Errorf("%s: %s", constants.ErrEntitySetNotFound, entitySetName)
+	}
+	entityType, exists := meta.EntityTypes[entitySet.EntityType]
+	if !exists {
+		return nil, "", fmt.Errorf("%s: %s", constants.ErrEntityTypeNotFound, entitySet.EntityType)
+	}
+
+	op := &models.BatchOperation{Path: path}
+
+	switch operation {
+	case constants.OpFilter:
+		op.Method = constants.GET
+		op.Query = batchQueryOptions(args["options"])
+	case constants.OpGet:
+		op.Method = constants.GET
+		op.Query = batchQueryOptions(args["options"])
+	case constants.OpCreate:
+		op.Method = constants.POST
+		data, _ := args["data"].(map[string]interface{})
+		if err := b.checkHiddenFields(entitySetName, data); err != nil {
+			return nil, "", err
+		}
+		if data != nil {
+			data = b.serializeStructured(entityType.Properties, data)
+		}
+		op.Body = b.convertBatchData(data)
+	case constants.OpUpdate:
+		op.Method = constants.PUT
+		if m, ok := args["method"].(string); ok && m != "" {
+			switch method := strings.ToUpper(m); method {
+			case constants.PUT, constants.PATCH, constants.MERGE:
+				op.Method = method
+			default:
+				return nil, "", fmt.Errorf("unsupported update method: %q (use PUT, PATCH, or MERGE)", m)
+			}
+		}
+		data, _ := args["data"].(map[string]interface{})
+		if err := b.checkHiddenFields(entitySetName, data); err != nil {
+			return nil, "", err
+		}
+		if data != nil {
+			data = b.serializeStructured(entityType.Properties, data)
+		}
+		op.Body = b.convertBatchData(data)
+	case constants.OpDelete:
+		op.Method = constants.DELETE
+	default:
+		return nil, "", fmt.Errorf("unsupported operation: %q", operation)
+	}
+
+	if !b.isOperationAllowed(entitySetName, entitySet, operation) {
+		return nil, "", fmt.Errorf("entity set %s does not allow %s", entitySetName, operation)
+	}
+
+	// Single-entity operations need the full key
+	if operation != constants.OpFilter && operation != constants.OpCreate {
+		keyArgs, _ := args["key"].(map[string]interface{})
+		op.Key = make(map[string]interface{})
+		for _, keyProp := range entityType.KeyProperties {
+			value, exists := keyArgs[keyProp]
+			if !exists {
+				return nil, "", fmt.Errorf("missing required key property: %s", keyProp)
+			}
+			op.Key[keyProp] = value
+		}
+	}
+
+	return op, entitySetName, nil
+}
+
+// resolveBatchReference resolves a Content-ID reference such as $1/Items to
+// the entity set its navigation property leads to. The reference must name an
+// earlier create of the batch.
+func (b *ODataMCPBridge) resolveBatchReference(ref string, created map[int]string) (string, error) {
+	meta := b.metadata.Load()
+	matches := batchReferencePattern.FindStringSubmatch(ref)
+	if matches == nil {
+		return "", fmt.Errorf("invalid entity_set reference %q, expected $<n>/<NavProperty>", ref)
+	}
+	position, _ := strconv.Atoi(matches[1])
+	source, exists := created[position]
+	if !exists {
+		return "", fmt.Errorf("entity_set reference %q does not refer to an earlier create", ref)
+	}
+
+	sourceSet := meta.EntitySets[source]
+	sourceType := meta.EntityTypes[sourceSet.EntityType]
+	for _, navProp := range sourceType.NavigationProps {
+		if navProp.Name != matches[2] {
+			continue
+		}
+		nav, ok := b.resolveNavigation(source, sourceSet, sourceType, navProp)
+		if !ok {
+			return "", fmt.Errorf("cannot resolve the target entity set of %s/%s", source, navProp.Name)
+		}
+		return nav.target, nil
+	}
+	return "", fmt.Errorf("%s has no navigation property %s", source, matches[2])
+}
+
+// convertBatchData applies the same payload conversions as the create/update tools
+func (b *ODataMCPBridge) convertBatchData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	// Convert numeric fields to strings for SAP OData v2 compatibility
+	data = utils.ConvertNumericsInMap(data)
+
+	// Convert date fields to OData legacy format if needed
+	if b.config.LegacyDates {
+		data = utils.ConvertDatesInMap(data, false)
+	}
+
+	return data
+}
+
+// batchQueryOptions converts the options object of a batch operation to query parameters
+func batchQueryOptions(raw interface{}) map[string]string {
+	options, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	query := make(map[string]string)
+	for k, v := range options {
+		if !strings.HasPrefix(k, "$") {
+			k = "$" + k
+		}
+		switch val := v.(type) {
+		case string:
+			query[k] = val
+		case float64:
+			query[k] = fmt.Sprintf("%d", int(val))
+		case bool:
+			query[k] = fmt.Sprintf("%t", val)
+		}
+	}
+	return query
+}
@@ -0,0 +1,244 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// boundTarget is an entity set or singleton a bound operation is called on
+type boundTarget struct {
+	name       string
+	entityType *models.EntityType
+	keyed      bool // Called on a single entity of an entity set, addressed by key
+}
+
+// generateBoundOperationTools creates a tool for each OData v4 bound function
+// and action on every entity set and singleton of its binding type
+func (b *ODataMCPBridge) generateBoundOperationTools() {
+	meta := b.metadata.Load()
+	for _, op := range meta.BoundOperations {
+		if !b.shouldIncludeFunction(op.Name) || !b.isFunctionAllowed(op) {
+			continue
+		}
+		for _, target := range b.boundTargets(op) {
+			// Functions read the bound entity, actions change it
+			operation := constants.OpGet
+			if op.IsAction {
+				operation = constants.OpUpdate
+			}
+			if b.isPermitted(target.name, operation) {
+				b.generateBoundOperationTool(target, op)
+			}
+		}
+	}
+}
+
+// boundTargets returns the entity sets and singletons a bound operation can be called on
+func (b *ODataMCPBridge) boundTargets(op *models.FunctionImport) []boundTarget {
+	meta := b.metadata.Load()
+	typeName, collection := collectionItemType(op.BindingType)
+	if !collection {
+		typeName = op.BindingType
+	}
+	typeName = unqualifiedName(typeName)
+
+	entityType, exists := meta.EntityTypes[typeName]
+	if !exists {
+		return nil
+	}
+
+	targets := make([]boundTarget, 0)
+	for name, entitySet := range meta.EntitySets {
+		if entitySet.EntityType == typeName && b.shouldIncludeEntity(name) {
+			targets = append(targets, boundTarget{name: name, entityType: entityType, keyed: !collection})
+		}
+	}
+	if !collection {
+		for name, singleton := range meta.Singletons {
+			if singleton.EntityType == typeName && b.shouldIncludeEntity(name) {
+				targets = append(targets, boundTarget{name: name, entityType: entityType})
+			}
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].name < targets[j].name })
+	return targets
+}
+
+// generateBoundOperationTool creates a tool that calls a bound operation on an
+// entity set, a single entity of the set, or a singleton
+func (b *ODataMCPBridge) generateBoundOperationTool(target boundTarget, op *models.FunctionImport) {
+	toolName := b.formatToolName(op.Name, target.name)
+
+	kind := "function"
+	if op.IsAction {
+		kind = "action"
+	}
+	description := fmt.Sprintf("Call bound %s %s on %s", kind, op.QualifiedName, target.name)
+	if target.keyed {
+		description = fmt.Sprintf("Call bound %s %s on a %s entity by key", kind, op.QualifiedName, target.name)
+	}
+	description = b.entityDescription(target.name, description)
+
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+
+	if target.keyed {
+		for _, keyProp := range target.entityType.KeyProperties {
+			for _, prop := range target.entityType.Properties {
+				if prop.Name == keyProp {
+					properties[keyProp] = b.keySchema(prop)
+					required = append(required, keyProp)
+					break
+				}
+			}
+		}
+	}
+
+	for _, param := range op.Parameters {
+		schema := b.schemaForType(param.Type)
+		schema["description"] = fmt.Sprintf("Parameter: %s", param.Name)
+		properties[param.Name] = schema
+
+		if !param.Nullable {
+			required = append(required, param.Name)
+		}
+	}
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites && op.IsAction {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
+
+	inputSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		inputSchema["required"] = required
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: inputSchema,
+	}
+
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleBoundOperationCall(ctx, target, op, args)
+	})
+	if b.config.ConfirmWrites && op.IsAction {
+		handler = b.withConfirmation(toolName, b.previewBoundOperation(target, op), handler)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   target.name,
+		Function:    op.QualifiedName,
+	}
+}
+
+// boundCall splits tool arguments into the key of the bound entity and the operation parameters
+func boundCall(target boundTarget, op *models.FunctionImport, args map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	var key map[string]interface{}
+	if target.keyed {
+		var err error
+		if key, err = entityKey(target.entityType, args); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	parameters := make(map[string]interface{})
+	for _, param := range op.Parameters {
+		if value, exists := args[param.Name]; exists {
+			parameters[param.Name] = value
+		} else if !param.Nullable {
+			return nil, nil, fmt.Errorf("missing required parameter: %s", param.Name)
+		}
+	}
+	return key, parameters, nil
+}
+
+func (b *ODataMCPBridge) handleBoundOperationCall(ctx context.Context, target boundTarget, op *models.FunctionImport, args map[string]interface{}) (interface{}, error) {
+	key, parameters, err := boundCall(target, op, args)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.clientFor(ctx).CallBoundOperation(ctx, target.name, key, op.QualifiedName, parameters, strings.ToUpper(op.HTTPMethod))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call bound operation: %w", err)
+	}
+	if resultSet := b.boundResultEntitySet(target, op); resultSet != "" {
+		b.hideFields(resultSet, response)
+	}
+
+	// Format response as JSON string
+	result, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+// boundResultEntitySet returns the entity set whose overrides apply to the
+// entities a bound operation returns: the bound target when it returns its
+// binding type, else the first entity set of the returned entity type
+func (b *ODataMCPBridge) boundResultEntitySet(target boundTarget, op *models.FunctionImport) string {
+	meta := b.metadata.Load()
+	typeName, collection := collectionItemType(op.ReturnType)
+	if !collection {
+		typeName = op.ReturnType
+	}
+	typeName = unqualifiedName(typeName)
+	if typeName == "" {
+		return ""
+	}
+	if typeName == target.entityType.Name {
+		return target.name
+	}
+
+	names := make([]string, 0)
+	for name, entitySet := range meta.EntitySets {
+		if entitySet.EntityType == typeName {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// previewBoundOperation describes a bound action call
+func (b *ODataMCPBridge) previewBoundOperation(target boundTarget, op *models.FunctionImport) previewFunc {
+	return func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		key, parameters, err := boundCall(target, op, args)
+		if err != nil {
+			return nil, err
+		}
+		preview := map[string]interface{}{
+			"operation":  "action",
+			"method":     op.HTTPMethod,
+			"url":        b.clientFor(ctx).BoundOperationURL(target.name, key, op.QualifiedName),
+			"parameters": parameters,
+		}
+		if key != nil {
+			preview["key"] = key
+		}
+		return preview, nil
+	}
+}
@@ -2,16 +2,22 @@ package bridge
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"os"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/zmcp/odata-mcp/internal/client"
 	"github.com/zmcp/odata-mcp/internal/config"
 	"github.com/zmcp/odata-mcp/internal/constants"
 	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/metadata"
 	"github.com/zmcp/odata-mcp/internal/models"
 	"github.com/zmcp/odata-mcp/internal/transport"
 	"github.com/zmcp/odata-mcp/internal/utils"
@@ -22,36 +28,123 @@ type ODataMCPBridge struct {
 	config     *config.Config
 	client     *client.ODataClient
 	server     *mcp.Server
-	metadata   *models.ODataMetadata
+	metadata   atomic.Pointer[models.ODataMetadata] // swapped by Reload while handlers run
+	tlsConfig  *tls.Config
 	tools      map[string]*models.ToolInfo
 	mu         sync.RWMutex
 	running    bool
 	stopChan   chan struct{}
+
+	// Per-session clients keep CSRF tokens and session cookies apart
+	// when several MCP sessions share one bridge
+	sessionClients map[string]*sessionClient
+	sessionMu      sync.Mutex
+
+	// One-time tokens of previewed writes (--confirm-writes)
+	confirmations *confirmationStore
+
+	// Serializes metadata reloads
+	reloadMu sync.Mutex
+
+	// Tools generated during a reload, registered only once it succeeded
+	pending []pendingTool
+	staging bool
+}
+
+// pendingTool is a tool generated during a reload that is not registered yet
+type pendingTool struct {
+	tool    *mcp.Tool
+	handler mcp.ToolHandler
+}
+
+// sessionClient is the OData client of one MCP session, bound to the
+// end-user credentials it was created for
+type sessionClient struct {
+	client      *client.ODataClient
+	fingerprint string
 }
 
 // NewODataMCPBridge creates a new bridge instance
 func NewODataMCPBridge(cfg *config.Config) (*ODataMCPBridge, error) {
+	return newBridge(cfg, mcp.NewServer(constants.MCPServerName, constants.MCPServerVersion))
+}
+
+// newBridge creates a bridge for one OData service that registers its tools on mcpServer
+func newBridge(cfg *config.Config, mcpServer *mcp.Server) (*ODataMCPBridge, error) {
 	// Create OData client
 	odataClient := client.NewODataClient(cfg.ServiceURL, cfg.Verbose)
 
+	// Configure TLS (CA bundle, client certificate, minimum version)
+	var tlsConfig *tls.Config
+	if cfg.HasTLSConfig() {
+		var err error
+		tlsConfig, err = client.BuildTLSConfig(client.TLSOptions{
+			CAFile:             cfg.CAFile,
+			CertFile:           cfg.ClientCert,
+			KeyFile:            cfg.ClientKey,
+			CertPassword:       cfg.ClientCertPassword,
+			MinVersion:         cfg.TLSMinVersion,
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		odataClient.SetTLSConfig(tlsConfig)
+	}
+
+	// Configure proxy and static request headers
+	if cfg.Proxy != "" {
+		if err := odataClient.SetProxy(cfg.Proxy, cfg.NoProxy); err != nil {
+			return nil, fmt.Errorf("failed to configure proxy: %w", err)
+		}
+	}
+	if len(cfg.ExtraHeaders) > 0 {
+		odataClient.SetExtraHeaders(cfg.ExtraHeaders)
+	}
+
+	// Configure the on-disk metadata cache
+	if cfg.MetadataCacheDir != "" {
+		cache, err := client.NewMetadataCache(cfg.MetadataCacheDir, cfg.MetadataCacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		odataClient.SetMetadataCache(cache)
+	}
+
 	// Configure authentication
 	if cfg.HasBasicAuth() {
 		odataClient.SetBasicAuth(cfg.Username, cfg.Password)
 	} else if cfg.HasCookieAuth() {
 		odataClient.SetCookies(cfg.Cookies)
+	} else if cfg.HasBearerToken() {
+		odataClient.SetBearerToken(cfg.BearerToken)
+	} else if cfg.HasOAuth2() {
+		odataClient.SetTokenSource(client.NewOAuth2TokenSource(client.OAuth2Config{
+			TokenURL:     cfg.OAuth2TokenURL,
+			ClientID:     cfg.OAuth2ClientID,
+			ClientSecret: cfg.OAuth2ClientSecret,
+			Scopes: strings.FieldsFunc(cfg.OAuth2Scopes, func(r rune) bool {
+				return r == ',' || r == ' '
+			}),
+			RefreshToken: cfg.OAuth2RefreshToken,
+			HTTPClient:   odataClient.HTTPClient(),
+		}, cfg.Verbose))
 	}
 
-	// Create MCP server
-	mcpServer := mcp.NewServer(constants.MCPServerName, constants.MCPServerVersion)
-
 	bridge := &ODataMCPBridge{
-		config:   cfg,
-		client:   odataClient,
-		server:   mcpServer,
-		tools:    make(map[string]*models.ToolInfo),
-		stopChan: make(chan struct{}),
+		config:         cfg,
+		client:         odataClient,
+		server:         mcpServer,
+		tlsConfig:      tlsConfig,
+		tools:          make(map[string]*models.ToolInfo),
+		stopChan:       make(chan struct{}),
+		sessionClients: make(map[string]*sessionClient),
+		confirmations:  newConfirmationStore(cfg.ConfirmTTL),
 	}
 
+	// Drop a session's OData client when its MCP session ends
+	mcpServer.OnSessionClosed(bridge.closeSessionClient)
+
 	// Initialize metadata and tools
 	if err := bridge.initialize(); err != nil {
 		return nil, fmt.Errorf("failed to initialize bridge: %w", err)
@@ -64,30 +157,93 @@ func NewODataMCPBridge(cfg *config.Config) (*ODataMCPBridge, error) {
 func (b *ODataMCPBridge) initialize() error {
 	ctx := context.Background()
 
-	// Fetch metadata
-	metadata, err := b.client.GetMetadata(ctx)
+	metadata, err := b.loadMetadata(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to fetch metadata: %w", err)
+		return err
 	}
 
-	b.metadata = metadata
+	b.metadata.Store(metadata)
 
 	// Generate tools
 	if err := b.generateTools(); err != nil {
 		return fmt.Errorf("failed to generate tools: %w", err)
 	}
+	b.generateResources()
 
 	return nil
 }
 
+// loadMetadata reads the metadata from --metadata-file or fetches it from the service
+func (b *ODataMCPBridge) loadMetadata(ctx context.Context) (*models.ODataMetadata, error) {
+	if b.config.MetadataFile != "" {
+		data, err := os.ReadFile(b.config.MetadataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read metadata file: %w", err)
+		}
+		if b.config.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Loading metadata from %s\n", b.config.MetadataFile)
+		}
+		return b.client.ParseMetadataDocument(data)
+	}
+
+	metadata, err := b.client.GetMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// clientFor returns the OData client for the MCP session of ctx.
+// Session-less transports such as stdio share the bridge's client.
+// Pass-through credentials and isolated requests get a client of their own
+// so CSRF tokens and session cookies issued to one caller are never sent on
+// behalf of another.
+func (b *ODataMCPBridge) clientFor(ctx context.Context) *client.ODataClient {
+	sessionID := transport.SessionIDFromContext(ctx)
+	creds := transport.CredentialsFromContext(ctx)
+
+	if sessionID == "" {
+		if creds != nil || transport.IsolatedFromContext(ctx) {
+			return b.client.NewSession()
+		}
+		return b.client
+	}
+
+	fingerprint := ""
+	if creds != nil {
+		fingerprint = creds.Fingerprint()
+	}
+
+	b.sessionMu.Lock()
+	defer b.sessionMu.Unlock()
+
+	sc, exists := b.sessionClients[sessionID]
+	if !exists || sc.fingerprint != fingerprint {
+		sc = &sessionClient{client: b.client.NewSession(), fingerprint: fingerprint}
+		b.sessionClients[sessionID] = sc
+	}
+	return sc.client
+}
+
+// closeSessionClient releases the OData client of an ended MCP session
+func (b *ODataMCPBridge) closeSessionClient(sessionID string) {
+	b.sessionMu.Lock()
+	delete(b.sessionClients, sessionID)
+	b.sessionMu.Unlock()
+}
+
 // generateTools creates MCP tools based on metadata
 func (b *ODataMCPBridge) generateTools() error {
+	meta := b.metadata.Load()
 	// 1. Generate service info tool first
 	b.generateServiceInfoTool()
+	if b.config.ReloadTool {
+		b.generateReloadTool()
+	}
 
 	// 2. Generate entity set tools in alphabetical order
-	entityNames := make([]string, 0, len(b.metadata.EntitySets))
-	for name := range b.metadata.EntitySets {
+	entityNames := make([]string, 0, len(meta.EntitySets))
+	for name := range meta.EntitySets {
 		if b.shouldIncludeEntity(name) {
 			entityNames = append(entityNames, name)
 		}
@@ -95,27 +251,58 @@ func (b *ODataMCPBridge) generateTools() error {
 	sort.Strings(entityNames)
 	
 	for _, name := range entityNames {
-		entitySet := b.metadata.EntitySets[name]
+		entitySet := meta.EntitySets[name]
 		b.generateEntitySetTools(name, entitySet)
 	}
 
-	// 3. Generate function import tools in alphabetical order
-	functionNames := make([]string, 0, len(b.metadata.FunctionImports))
-	for name := range b.metadata.FunctionImports {
-		if b.shouldIncludeFunction(name) {
+	// 3. Generate singleton tools in alphabetical order
+	singletonNames := make([]string, 0, len(meta.Singletons))
+	for name := range meta.Singletons {
+		if b.shouldIncludeEntity(name) {
+			singletonNames = append(singletonNames, name)
+		}
+	}
+	sort.Strings(singletonNames)
+
+	for _, name := range singletonNames {
+		b.generateSingletonTools(name, meta.Singletons[name])
+	}
+
+	// 4. Generate function import tools in alphabetical order
+	functionNames := make([]string, 0, len(meta.FunctionImports))
+	for name := range meta.FunctionImports {
+		if b.shouldIncludeFunction(name) && b.isFunctionAllowed(meta.FunctionImports[name]) {
 			functionNames = append(functionNames, name)
 		}
 	}
 	sort.Strings(functionNames)
 	
 	for _, name := range functionNames {
-		function := b.metadata.FunctionImports[name]
+		function := meta.FunctionImports[name]
 		b.generateFunctionTool(name, function)
 	}
 
+	// 5. Generate tools for functions and actions bound to entity sets and singletons
+	b.generateBoundOperationTools()
+
+	// 6. Generate batch tool when any entity sets are exposed
+	if len(entityNames) > 0 {
+		b.generateBatchTool()
+	}
+
 	return nil
 }
 
+// addTool registers a tool on the MCP server with the bridge's argument validation setting
+func (b *ODataMCPBridge) addTool(tool *mcp.Tool, handler mcp.ToolHandler) {
+	tool.ValidateArguments = b.config.ValidateArgs
+	if b.staging {
+		b.pending = append(b.pending, pendingTool{tool: tool, handler: handler})
+		return
+	}
+	b.server.AddTool(tool, handler)
+}
+
 // shouldIncludeEntity checks if an entity should be included based on filters
 func (b *ODataMCPBridge) shouldIncludeEntity(entityName string) bool {
 	if len(b.config.AllowedEntities) == 0 {
@@ -189,7 +376,7 @@ func (b *ODataMCPBridge) generateServiceInfoTool() {
 		return b.handleServiceInfo(ctx, args)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -201,43 +388,53 @@ func (b *ODataMCPBridge) generateServiceInfoTool() {
 
 // generateEntitySetTools creates tools for an entity set
 func (b *ODataMCPBridge) generateEntitySetTools(entitySetName string, entitySet *models.EntitySet) {
+	meta := b.metadata.Load()
 	// Get entity type
-	entityType, exists := b.metadata.EntityTypes[entitySet.EntityType]
+	entityType, exists := meta.EntityTypes[entitySet.EntityType]
 	if !exists {
 		if b.config.Verbose {
-			fmt.Printf("[VERBOSE] Entity type not found for entity set %s: %s\n", entitySetName, entitySet.EntityType)
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Entity type not found for entity set %s: %s\n", entitySetName, entitySet.EntityType)
 		}
 		return
 	}
 
 	// Generate filter/list tool
-	b.generateFilterTool(entitySetName, entitySet, entityType)
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpFilter) {
+		b.generateFilterTool(entitySetName, entitySet, entityType)
+	}
 
 	// Generate count tool  
-	b.generateCountTool(entitySetName, entitySet, entityType)
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpCount) {
+		b.generateCountTool(entitySetName, entitySet, entityType)
+	}
 
 	// Generate search tool if supported
-	if entitySet.Searchable {
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpSearch) {
 		b.generateSearchTool(entitySetName, entitySet, entityType)
 	}
 
 	// Generate get tool
-	b.generateGetTool(entitySetName, entitySet, entityType)
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpGet) {
+		b.generateGetTool(entitySetName, entitySet, entityType)
+	}
 
 	// Generate create tool if allowed
-	if entitySet.Creatable {
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpCreate) {
 		b.generateCreateTool(entitySetName, entitySet, entityType)
 	}
 
 	// Generate update tool if allowed
-	if entitySet.Updatable {
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpUpdate) {
 		b.generateUpdateTool(entitySetName, entitySet, entityType)
 	}
 
 	// Generate delete tool if allowed
-	if entitySet.Deletable {
+	if b.isOperationAllowed(entitySetName, entitySet, constants.OpDelete) {
 		b.generateDeleteTool(entitySetName, entitySet, entityType)
 	}
+
+	// Generate navigation property and link tools
+	b.generateNavigationTools(entitySetName, entitySet, entityType)
 }
 
 // generateFilterTool creates a filter/list tool for an entity set
@@ -245,10 +442,45 @@ func (b *ODataMCPBridge) generateFilterTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpFilter, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("List/filter %s entities with OData query options", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("List/filter %s entities with OData query options", entitySetName))
 
-	// Build input schema with standard OData parameters
-	properties := map[string]interface{}{
+	// Build input schema with standard OData parameters the entity set supports
+	properties := filterProperties()
+	restrictQueryOptions(entitySet, properties)
+	inputSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	// SAP services reject queries without a filter on required-in-filter properties
+	if b.annotateFilterProperties(entitySetName, entitySet, entityType, properties) {
+		inputSchema["required"] = []string{"$filter"}
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: inputSchema,
+	}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleEntityFilter(ctx, entitySetName, args)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   entitySetName,
+		Operation:   constants.OpFilter,
+	}
+}
+
+// filterProperties returns the input schema properties of the OData query options for collections
+func filterProperties() map[string]interface{} {
+	return map[string]interface{}{
 		"$filter": map[string]interface{}{
 			"type":        "string",
 			"description": "OData filter expression",
@@ -278,29 +510,6 @@ func (b *ODataMCPBridge) generateFilterTool(entitySetName string, entitySet *mod
 			"description": "Include total count of matching entities (v4) or use $inlinecount for v2",
 		},
 	}
-
-	tool := &mcp.Tool{
-		Name:        toolName,
-		Description: description,
-		InputSchema: map[string]interface{}{
-			"type":       "object",
-			"properties": properties,
-		},
-	}
-
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-		return b.handleEntityFilter(ctx, entitySetName, args)
-	}
-
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
-		Name:        toolName,
-		Description: description,
-		EntitySet:   entitySetName,
-		Operation:   constants.OpFilter,
-	}
 }
 
 // generateCountTool creates a count tool for an entity set
@@ -308,27 +517,34 @@ func (b *ODataMCPBridge) generateCountTool(entitySetName string, entitySet *mode
 	opName := constants.GetToolOperationName(constants.OpCount, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Get count of %s entities with optional filter", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("Get count of %s entities with optional filter", entitySetName))
+
+	properties := map[string]interface{}{
+		"$filter": map[string]interface{}{
+			"type":        "string",
+			"description": "OData filter expression",
+		},
+	}
+	restrictQueryOptions(entitySet, properties)
+	inputSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if b.annotateFilterProperties(entitySetName, entitySet, entityType, properties) {
+		inputSchema["required"] = []string{"$filter"}
+	}
 
 	tool := &mcp.Tool{
 		Name:        toolName,
 		Description: description,
-		InputSchema: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"$filter": map[string]interface{}{
-					"type":        "string",
-					"description": "OData filter expression",
-				},
-			},
-		},
+		InputSchema: inputSchema,
 	}
 
 	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityCount(ctx, entitySetName, args)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -344,7 +560,7 @@ func (b *ODataMCPBridge) generateSearchTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpSearch, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Full-text search %s entities", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("Full-text search %s entities", entitySetName))
 
 	tool := &mcp.Tool{
 		Name:        toolName,
@@ -373,7 +589,7 @@ func (b *ODataMCPBridge) generateSearchTool(entitySetName string, entitySet *mod
 		return b.handleEntitySearch(ctx, entitySetName, args)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -389,7 +605,7 @@ func (b *ODataMCPBridge) generateGetTool(entitySetName string, entitySet *models
 	opName := constants.GetToolOperationName(constants.OpGet, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Get a single %s entity by key", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("Get a single %s entity by key", entitySetName))
 
 	// Build key properties for input schema
 	properties := make(map[string]interface{})
@@ -398,10 +614,7 @@ func (b *ODataMCPBridge) generateGetTool(entitySetName string, entitySet *models
 	for _, keyProp := range entityType.KeyProperties {
 		for _, prop := range entityType.Properties {
 			if prop.Name == keyProp {
-				properties[keyProp] = map[string]interface{}{
-					"type":        b.getJSONSchemaType(prop.Type),
-					"description": fmt.Sprintf("Key property: %s", keyProp),
-				}
+				properties[keyProp] = b.keySchema(prop)
 				required = append(required, keyProp)
 				break
 			}
@@ -436,7 +649,7 @@ func (b *ODataMCPBridge) generateGetTool(entitySetName string, entitySet *models
 		return b.handleEntityGet(ctx, entitySetName, entityType, args)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -452,27 +665,25 @@ func (b *ODataMCPBridge) generateCreateTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpCreate, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Create a new %s entity", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("Create a new %s entity", entitySetName))
 
 	// Build properties for input schema based on entity type
 	properties := make(map[string]interface{})
 	required := make([]string, 0)
 
 	for _, prop := range entityType.Properties {
-		// Skip key properties that are auto-generated
-		if prop.IsKey {
+		// Skip key properties that are auto-generated and read-only properties
+		if prop.IsKey || !prop.Creatable || b.isHiddenField(entitySetName, prop) {
 			continue
 		}
 
-		properties[prop.Name] = map[string]interface{}{
-			"type":        b.getJSONSchemaType(prop.Type),
-			"description": fmt.Sprintf("Property: %s", prop.Name),
-		}
+		properties[prop.Name] = b.propertySchema(entitySetName, prop)
 
 		if !prop.Nullable {
 			required = append(required, prop.Name)
 		}
 	}
+	b.addDryRunParameter(properties)
 
 	inputSchema := map[string]interface{}{
 		"type":       "object",
@@ -489,11 +700,11 @@ func (b *ODataMCPBridge) generateCreateTool(entitySetName string, entitySet *mod
 		InputSchema: inputSchema,
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-		return b.handleEntityCreate(ctx, entitySetName, args)
-	}
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleEntityCreate(ctx, entitySetName, entityType, args)
+	})
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -509,7 +720,7 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpUpdate, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Update an existing %s entity", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("Update an existing %s entity", entitySetName))
 
 	// Build properties for input schema
 	properties := make(map[string]interface{})
@@ -519,10 +730,7 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 	for _, keyProp := range entityType.KeyProperties {
 		for _, prop := range entityType.Properties {
 			if prop.Name == keyProp {
-				properties[keyProp] = map[string]interface{}{
-					"type":        b.getJSONSchemaType(prop.Type),
-					"description": fmt.Sprintf("Key property: %s", keyProp),
-				}
+				properties[keyProp] = b.keySchema(prop)
 				required = append(required, keyProp)
 				break
 			}
@@ -531,11 +739,8 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 
 	// Add updatable properties (optional)
 	for _, prop := range entityType.Properties {
-		if !prop.IsKey {
-			properties[prop.Name] = map[string]interface{}{
-				"type":        b.getJSONSchemaType(prop.Type),
-				"description": fmt.Sprintf("Property: %s", prop.Name),
-			}
+		if !prop.IsKey && prop.Updatable && !b.isHiddenField(entitySetName, prop) {
+			properties[prop.Name] = b.propertySchema(entitySetName, prop)
 		}
 	}
 
@@ -546,6 +751,12 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 		"enum":        []string{"PUT", "PATCH", "MERGE"},
 		"default":     "PUT",
 	}
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
 
 	tool := &mcp.Tool{
 		Name:        toolName,
@@ -557,11 +768,14 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityUpdate(ctx, entitySetName, entityType, args)
+	})
+	if b.config.ConfirmWrites {
+		handler = b.withConfirmation(toolName, b.previewUpdate(entitySetName, entityType), handler)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -577,7 +791,7 @@ func (b *ODataMCPBridge) generateDeleteTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpDelete, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Delete a %s entity", entitySetName)
+	description := b.entityDescription(entitySetName, fmt.Sprintf("Delete a %s entity", entitySetName))
 
 	// Build key properties for input schema
 	properties := make(map[string]interface{})
@@ -586,15 +800,18 @@ func (b *ODataMCPBridge) generateDeleteTool(entitySetName string, entitySet *mod
 	for _, keyProp := range entityType.KeyProperties {
 		for _, prop := range entityType.Properties {
 			if prop.Name == keyProp {
-				properties[keyProp] = map[string]interface{}{
-					"type":        b.getJSONSchemaType(prop.Type),
-					"description": fmt.Sprintf("Key property: %s", keyProp),
-				}
+				properties[keyProp] = b.keySchema(prop)
 				required = append(required, keyProp)
 				break
 			}
 		}
 	}
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
 
 	tool := &mcp.Tool{
 		Name:        toolName,
@@ -606,11 +823,14 @@ func (b *ODataMCPBridge) generateDeleteTool(entitySetName string, entitySet *mod
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityDelete(ctx, entitySetName, entityType, args)
+	})
+	if b.config.ConfirmWrites {
+		handler = b.withConfirmation(toolName, b.previewDelete(entitySetName, entityType), handler)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -633,16 +853,21 @@ func (b *ODataMCPBridge) generateFunctionTool(functionName string, function *mod
 
 	for _, param := range function.Parameters {
 		if param.Mode == "In" || param.Mode == "InOut" {
-			properties[param.Name] = map[string]interface{}{
-				"type":        b.getJSONSchemaType(param.Type),
-				"description": fmt.Sprintf("Parameter: %s", param.Name),
-			}
+			schema := b.schemaForType(param.Type)
+			schema["description"] = fmt.Sprintf("Parameter: %s", param.Name)
+			properties[param.Name] = schema
 
 			if !param.Nullable {
 				required = append(required, param.Name)
 			}
 		}
 	}
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites && strings.EqualFold(function.HTTPMethod, constants.POST) {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
 
 	inputSchema := map[string]interface{}{
 		"type":       "object",
@@ -659,11 +884,14 @@ func (b *ODataMCPBridge) generateFunctionTool(functionName string, function *mod
 		InputSchema: inputSchema,
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleFunctionCall(ctx, functionName, function, args)
+	})
+	if b.config.ConfirmWrites && strings.EqualFold(function.HTTPMethod, constants.POST) {
+		handler = b.withConfirmation(toolName, b.previewFunction(functionName, function), handler)
 	}
 
-	b.server.AddTool(tool, handler)
+	b.addTool(tool, handler)
 
 	// Track tool info
 	b.tools[toolName] = &models.ToolInfo{
@@ -748,6 +976,8 @@ func (b *ODataMCPBridge) Run() error {
 	b.running = true
 	b.mu.Unlock()
 
+	b.startRefresh()
+
 	// Start MCP server
 	return b.server.Run()
 }
@@ -768,6 +998,7 @@ func (b *ODataMCPBridge) Stop() {
 
 // GetTraceInfo returns comprehensive trace information
 func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
+	meta := b.metadata.Load()
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -776,8 +1007,43 @@ func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
 		authType = fmt.Sprintf("Basic (user: %s)", b.config.Username)
 	} else if b.config.HasCookieAuth() {
 		authType = fmt.Sprintf("Cookie (%d cookies)", len(b.config.Cookies))
+	} else if b.config.HasBearerToken() {
+		authType = "Bearer token"
+	} else if b.config.HasOAuth2() {
+		grant := "client credentials"
+		if b.config.OAuth2RefreshToken != "" {
+			grant = "refresh token"
+		}
+		authType = fmt.Sprintf("OAuth2 %s (client: %s, token URL: %s)", grant, b.config.OAuth2ClientID, b.config.OAuth2TokenURL)
+	}
+
+	var tlsInfo *models.TLSInfo
+	if b.tlsConfig != nil {
+		tlsInfo = &models.TLSInfo{
+			CAFile:             b.config.CAFile,
+			MinVersion:         client.TLSVersionName(b.tlsConfig.MinVersion),
+			InsecureSkipVerify: b.tlsConfig.InsecureSkipVerify,
+		}
+		if len(b.tlsConfig.Certificates) > 0 && b.tlsConfig.Certificates[0].Leaf != nil {
+			leaf := b.tlsConfig.Certificates[0].Leaf
+			tlsInfo.ClientCertificate = leaf.Subject.String()
+			tlsInfo.ClientCertExpires = leaf.NotAfter.UTC().Format(time.RFC3339)
+		}
 	}
 
+	// Never show proxy credentials or header values
+	proxy := ""
+	if b.config.Proxy != "" {
+		if proxyURL, err := url.Parse(b.config.Proxy); err == nil {
+			proxy = proxyURL.Redacted()
+		}
+	}
+	headerNames := make([]string, 0, len(b.config.ExtraHeaders))
+	for name := range b.config.ExtraHeaders {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
 	toolNaming := "Postfix"
 	if !b.config.UsePostfix() {
 		toolNaming = "Prefix"
@@ -789,6 +1055,7 @@ func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
 	}
 
 	return &models.TraceInfo{
+		ServiceName:     b.config.Name,
 		ServiceURL:      b.config.ServiceURL,
 		MCPName:         constants.MCPServerName,
 		ToolNaming:      toolNaming,
@@ -798,22 +1065,45 @@ func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
 		SortTools:       b.config.SortTools,
 		EntityFilter:    b.config.AllowedEntities,
 		FunctionFilter:  b.config.AllowedFunctions,
+		ReadOnly:        b.config.ReadOnly,
+		NoPostFunctions: b.config.NoPostFunctions,
+		OperationRules:  b.config.EntityOperations,
+		DryRun:          b.config.DryRun,
 		Authentication:  authType,
+		TLS:             tlsInfo,
+		Proxy:           proxy,
+		ExtraHeaders:    headerNames,
+		UnresolvedSets:  b.unresolvedEntitySets(),
 		MetadataSummary: models.MetadataSummary{
-			EntityTypes:     len(b.metadata.EntityTypes),
-			EntitySets:      len(b.metadata.EntitySets),
-			FunctionImports: len(b.metadata.FunctionImports),
+			EntityTypes:     len(meta.EntityTypes),
+			EntitySets:      len(meta.EntitySets),
+			FunctionImports: len(meta.FunctionImports),
+			Singletons:      len(meta.Singletons),
 		},
 		RegisteredTools: tools,
 		TotalTools:      len(tools),
 	}, nil
 }
 
+// unresolvedEntitySets lists the entity sets that get no tools because their
+// entity type is not in the metadata, e.g. "Orders (SALES.Order)"
+func (b *ODataMCPBridge) unresolvedEntitySets() []string {
+	meta := b.metadata.Load()
+	unresolved := metadata.UnresolvedEntitySets(meta)
+	names := make([]string, 0, len(unresolved))
+	for name, entityType := range unresolved {
+		names = append(names, fmt.Sprintf("%s (%s)", name, entityType))
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Handler implementations would go here...
 // These would be the actual implementations that call the OData client
 // and return formatted responses. For brevity, I'm showing the signatures:
 
 func (b *ODataMCPBridge) handleServiceInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	meta := b.metadata.Load()
 	includeMetadata := false
 	if val, ok := args["include_metadata"].(bool); ok {
 		includeMetadata = val
@@ -821,19 +1111,52 @@ func (b *ODataMCPBridge) handleServiceInfo(ctx context.Context, args map[string]
 
 	info := map[string]interface{}{
 		"service_url": b.config.ServiceURL,
-		"entity_sets": len(b.metadata.EntitySets),
-		"entity_types": len(b.metadata.EntityTypes),
-		"function_imports": len(b.metadata.FunctionImports),
-		"schema_namespace": b.metadata.SchemaNamespace,
-		"container_name": b.metadata.ContainerName,
-		"version": b.metadata.Version,
-		"parsed_at": b.metadata.ParsedAt.Format("2006-01-02T15:04:05Z"),
+		"entity_sets": len(meta.EntitySets),
+		"entity_types": len(meta.EntityTypes),
+		"function_imports": len(meta.FunctionImports),
+		"schema_namespace": meta.SchemaNamespace,
+		"container_name": meta.ContainerName,
+		"version": meta.Version,
+		"parsed_at": meta.ParsedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if b.config.Name != "" {
+		info["service_name"] = b.config.Name
+	}
+	if b.config.ReadOnly {
+		info["read_only"] = true
+	}
+	if b.config.DryRun {
+		info["dry_run"] = true
+	}
+	if len(meta.Singletons) > 0 {
+		info["singletons"] = len(meta.Singletons)
+	}
+	if len(meta.BoundOperations) > 0 {
+		info["bound_operations"] = len(meta.BoundOperations)
 	}
 
 	if includeMetadata {
-		info["entity_sets_detail"] = b.metadata.EntitySets
-		info["entity_types_detail"] = b.metadata.EntityTypes
-		info["function_imports_detail"] = b.metadata.FunctionImports
+		info["entity_sets_detail"] = meta.EntitySets
+		info["entity_types_detail"] = meta.EntityTypes
+		info["function_imports_detail"] = meta.FunctionImports
+		if len(meta.Singletons) > 0 {
+			info["singletons_detail"] = meta.Singletons
+		}
+		if len(meta.BoundOperations) > 0 {
+			info["bound_operations_detail"] = meta.BoundOperations
+		}
+		if len(meta.Associations) > 0 {
+			info["associations_detail"] = meta.Associations
+			info["association_sets_detail"] = meta.AssociationSets
+		}
+		info["relationships"] = b.relationships()
+		if len(meta.ComplexTypes) > 0 {
+			info["complex_types_detail"] = meta.ComplexTypes
+		}
+		if len(meta.EnumTypes) > 0 {
+			info["enum_types_detail"] = meta.EnumTypes
+		}
 	}
 
 	response, err := json.Marshal(info)
@@ -846,9 +1169,37 @@ func (b *ODataMCPBridge) handleServiceInfo(ctx context.Context, args map[string]
 
 func (b *ODataMCPBridge) handleEntityFilter(ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
 	// Build query options from arguments using standard OData parameters
-	options := make(map[string]string)
+	options := filterOptions(args)
+	if err := b.checkRequiredFilters(entitySetName, options); err != nil {
+		return nil, err
+	}
 	
-	// Handle each OData parameter
+	// Call OData client to get entity set
+	response, err := b.clientFor(ctx).GetEntitySet(ctx, entitySetName, options)
+	if err != nil {
+		if b.config.VerboseErrors {
+			return nil, fmt.Errorf("failed to filter entities from %s with options %v: %w", entitySetName, options, err)
+		}
+		return nil, fmt.Errorf("failed to filter entities: %w", err)
+	}
+	
+	// Enhance response based on configuration
+	enhancedResponse := b.enhanceResponse(response, options)
+	b.hideFields(entitySetName, enhancedResponse)
+	
+	// Format response as JSON string
+	result, err := json.Marshal(enhancedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	
+	return string(result), nil
+}
+
+// filterOptions builds the query options of a collection request from the tool arguments
+func filterOptions(args map[string]interface{}) map[string]string {
+	options := make(map[string]string)
+
 	if filter, ok := args["$filter"].(string); ok && filter != "" {
 		options[constants.QueryFilter] = filter
 	}
@@ -873,26 +1224,7 @@ func (b *ODataMCPBridge) handleEntityFilter(ctx context.Context, entitySetName s
 		// The client will automatically translate this to $count=true for v4
 		options[constants.QueryInlineCount] = "allpages"
 	}
-	
-	// Call OData client to get entity set
-	response, err := b.client.GetEntitySet(ctx, entitySetName, options)
-	if err != nil {
-		if b.config.VerboseErrors {
-			return nil, fmt.Errorf("failed to filter entities from %s with options %v: %w", entitySetName, options, err)
-		}
-		return nil, fmt.Errorf("failed to filter entities: %w", err)
-	}
-	
-	// Enhance response based on configuration
-	enhancedResponse := b.enhanceResponse(response, options)
-	
-	// Format response as JSON string
-	result, err := json.Marshal(enhancedResponse)
-	if err != nil {
-		return nil, fmt.Errorf("failed to format response: %w", err)
-	}
-	
-	return string(result), nil
+	return options
 }
 
 // enhanceResponse enhances OData response based on configuration options
@@ -1100,9 +1432,12 @@ func (b *ODataMCPBridge) handleEntityCount(ctx context.Context, entitySetName st
 	// Add $inlinecount=allpages to get inline count (OData v2 syntax)
 	options[constants.QueryInlineCount] = "allpages"
 	options[constants.QueryTop] = "0" // We only want the count, not the data
+	if err := b.checkRequiredFilters(entitySetName, options); err != nil {
+		return nil, err
+	}
 	
 	// Call OData client to get count
-	response, err := b.client.GetEntitySet(ctx, entitySetName, options)
+	response, err := b.clientFor(ctx).GetEntitySet(ctx, entitySetName, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get entity count: %w", err)
 	}
@@ -1140,10 +1475,11 @@ func (b *ODataMCPBridge) handleEntitySearch(ctx context.Context, entitySetName s
 	}
 	
 	// Call OData client to search entities
-	response, err := b.client.GetEntitySet(ctx, entitySetName, options)
+	response, err := b.clientFor(ctx).GetEntitySet(ctx, entitySetName, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search entities: %w", err)
 	}
+	b.hideFields(entitySetName, response)
 	
 	// Format response as JSON string
 	result, err := json.Marshal(response)
@@ -1175,10 +1511,11 @@ func (b *ODataMCPBridge) handleEntityGet(ctx context.Context, entitySetName stri
 	}
 	
 	// Call OData client to get entity
-	response, err := b.client.GetEntity(ctx, entitySetName, key, options)
+	response, err := b.clientFor(ctx).GetEntity(ctx, entitySetName, key, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get entity: %w", err)
 	}
+	b.hideFields(entitySetName, response)
 	
 	// Format response as JSON string
 	result, err := json.Marshal(response)
@@ -1189,7 +1526,7 @@ func (b *ODataMCPBridge) handleEntityGet(ctx context.Context, entitySetName stri
 	return string(result), nil
 }
 
-func (b *ODataMCPBridge) handleEntityCreate(ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+func (b *ODataMCPBridge) handleEntityCreate(ctx context.Context, entitySetName string, entityType *models.EntityType, args map[string]interface{}) (interface{}, error) {
 	// All arguments are the entity data (excluding system parameters)
 	entityData := make(map[string]interface{})
 	for k, v := range args {
@@ -1198,6 +1535,10 @@ func (b *ODataMCPBridge) handleEntityCreate(ctx context.Context, entitySetName s
 			entityData[k] = v
 		}
 	}
+	if err := b.checkHiddenFields(entitySetName, entityData); err != nil {
+		return nil, err
+	}
+	entityData = b.serializeStructured(entityType.Properties, entityData)
 	
 	// Convert numeric fields to strings for SAP OData v2 compatibility
 	// This prevents "Failed to read property 'Quantity' at offset" errors
@@ -1209,13 +1550,14 @@ func (b *ODataMCPBridge) handleEntityCreate(ctx context.Context, entitySetName s
 	}
 	
 	// Call OData client to create entity
-	response, err := b.client.CreateEntity(ctx, entitySetName, entityData)
+	response, err := b.clientFor(ctx).CreateEntity(ctx, entitySetName, entityData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create entity: %w", err)
 	}
 	
 	// Enhance response (includes date conversion if enabled)
 	response = b.enhanceResponse(response, make(map[string]string))
+	b.hideFields(entitySetName, response)
 	
 	// Format response as JSON string
 	result, err := json.Marshal(response)
@@ -1262,6 +1604,10 @@ func (b *ODataMCPBridge) handleEntityUpdate(ctx context.Context, entitySetName s
 			return nil, fmt.Errorf("missing required key property: %s", keyProp)
 		}
 	}
+	if err := b.checkHiddenFields(entitySetName, updateData); err != nil {
+		return nil, err
+	}
+	updateData = b.serializeStructured(entityType.Properties, updateData)
 	
 	// Convert numeric fields to strings for SAP OData v2 compatibility
 	// This prevents "Failed to read property 'Quantity' at offset" errors
@@ -1273,13 +1619,14 @@ func (b *ODataMCPBridge) handleEntityUpdate(ctx context.Context, entitySetName s
 	}
 	
 	// Call OData client to update entity
-	response, err := b.client.UpdateEntity(ctx, entitySetName, key, updateData, method)
+	response, err := b.clientFor(ctx).UpdateEntity(ctx, entitySetName, key, updateData, method)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update entity: %w", err)
 	}
 	
 	// Enhance response (includes date conversion if enabled)
 	response = b.enhanceResponse(response, make(map[string]string))
+	b.hideFields(entitySetName, response)
 	
 	// Format response as JSON string
 	result, err := json.Marshal(response)
@@ -1302,7 +1649,7 @@ func (b *ODataMCPBridge) handleEntityDelete(ctx context.Context, entitySetName s
 	}
 	
 	// Call OData client to delete entity
-	_, err := b.client.DeleteEntity(ctx, entitySetName, key)
+	_, err := b.clientFor(ctx).DeleteEntity(ctx, entitySetName, key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete entity: %w", err)
 	}
@@ -1331,7 +1678,7 @@ func (b *ODataMCPBridge) handleFunctionCall(ctx context.Context, functionName st
 	}
 	
 	// Call OData client to execute function
-	response, err := b.client.CallFunction(ctx, functionName, parameters, method)
+	response, err := b.clientFor(ctx).CallFunction(ctx, functionName, parameters, method)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call function: %w", err)
 	}
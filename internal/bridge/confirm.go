@@ -0,0 +1,333 @@
+package bridge
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// confirmationTokenParam is the tool argument that carries a confirmation token
+const confirmationTokenParam = "_confirmation_token"
+
+// previewFunc describes what a write tool call would do, without doing it.
+// A nil preview means the call needs no confirmation.
+type previewFunc func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error)
+
+// pendingWrite is a previewed write waiting for confirmation
+type pendingWrite struct {
+	digest  string
+	expires time.Time
+}
+
+// confirmationStore issues one-time tokens that authorize exactly one previewed write
+type confirmationStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	pending map[string]pendingWrite
+}
+
+func newConfirmationStore(ttl time.Duration) *confirmationStore {
+	if ttl <= 0 {
+		ttl = constants.DefaultConfirmationTTL
+	}
+	return &confirmationStore{
+		ttl:     ttl,
+		pending: make(map[string]pendingWrite),
+	}
+}
+
+// issue creates a token for the write identified by digest
+func (s *confirmationStore) issue(digest string) (string, time.Time, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	expires := time.Now().Add(s.ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired tokens so abandoned previews do not accumulate
+	now := time.Now()
+	for t, p := range s.pending {
+		if now.After(p.expires) {
+			delete(s.pending, t)
+		}
+	}
+	s.pending[token] = pendingWrite{digest: digest, expires: expires}
+	return token, expires, nil
+}
+
+// redeem consumes a token. It fails if the token is unknown, expired or was
+// issued for a different write. A token can only be redeemed once.
+func (s *confirmationStore) redeem(token, digest string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.pending[token]
+	if !ok {
+		return fmt.Errorf("%s: unknown or already used confirmation token", constants.ErrConfirmationFailed)
+	}
+	delete(s.pending, token)
+
+	if time.Now().After(p.expires) {
+		return fmt.Errorf("%s: confirmation token expired, call the tool again without it for a new preview", constants.ErrConfirmationFailed)
+	}
+	if p.digest != digest {
+		return fmt.Errorf("%s: arguments differ from the previewed call, call the tool again without a token for a new preview", constants.ErrConfirmationFailed)
+	}
+	return nil
+}
+
+// writeDigest binds a confirmation to the tool, the MCP session and the exact arguments
+func writeDigest(ctx context.Context, toolName string, args map[string]interface{}) (string, error) {
+	// encoding/json sorts map keys, so equal arguments give equal payloads
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	sum := sha256.Sum256([]byte(toolName + "\n" + transport.SessionIDFromContext(ctx) + "\n" + string(payload)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// withConfirmation wraps a write tool handler with two-phase confirmation. A call
+// without a token returns the preview and a token; the write only runs when the
+// same arguments are sent again together with that token.
+func (b *ODataMCPBridge) withConfirmation(toolName string, preview previewFunc, handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		writeArgs := make(map[string]interface{}, len(args))
+		for k, v := range args {
+			if k != confirmationTokenParam {
+				writeArgs[k] = v
+			}
+		}
+		// A dry run writes nothing, so there is nothing to confirm. The client
+		// records instead of sending even if the handler ignores dry runs.
+		if b.isDryRun(writeArgs) {
+			dryRunCtx, _ := client.WithDryRun(ctx)
+			return handler(dryRunCtx, writeArgs)
+		}
+
+		digest, err := writeDigest(ctx, toolName, writeArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		if token, _ := args[confirmationTokenParam].(string); token != "" {
+			if err := b.confirmations.redeem(token, digest); err != nil {
+				return nil, err
+			}
+			return handler(ctx, writeArgs)
+		}
+
+		result, err := preview(ctx, writeArgs)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			// Nothing in this call needs confirmation
+			return handler(ctx, writeArgs)
+		}
+		token, expires, err := b.confirmations.issue(digest)
+		if err != nil {
+			return nil, err
+		}
+		result["confirmation_required"] = true
+		result["confirmation_token"] = token
+		result["expires_at"] = expires.UTC().Format(time.RFC3339)
+		result["message"] = fmt.Sprintf("Nothing was changed. To execute, call %s again with the same arguments and %s set to the token.", toolName, confirmationTokenParam)
+
+		response, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(response), nil
+	}
+}
+
+// addConfirmationParameter documents the confirmation token in a tool schema
+func (b *ODataMCPBridge) addConfirmationParameter(properties map[string]interface{}) {
+	properties[confirmationTokenParam] = map[string]interface{}{
+		"type":        "string",
+		"description": "Confirmation token from the preview returned by a first call without it. Required to execute the write.",
+	}
+}
+
+// confirmationDescription tells the model about the two-phase flow
+func confirmationDescription(description string) string {
+	return description + ". Requires confirmation: the first call only returns a preview and a confirmation token"
+}
+
+// entityKey extracts the key properties of an entity type from tool arguments
+func entityKey(entityType *models.EntityType, args map[string]interface{}) (map[string]interface{}, error) {
+	key := make(map[string]interface{})
+	for _, keyProp := range entityType.KeyProperties {
+		value, exists := args[keyProp]
+		if !exists {
+			return nil, fmt.Errorf("missing required key property: %s", keyProp)
+		}
+		key[keyProp] = value
+	}
+	return key, nil
+}
+
+// currentEntity fetches the entity a write would change, for the preview
+func (b *ODataMCPBridge) currentEntity(ctx context.Context, entitySetName string, key map[string]interface{}) (map[string]interface{}, error) {
+	response, err := b.clientFor(ctx).GetEntity(ctx, entitySetName, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entity for preview: %w", err)
+	}
+	entity, _ := b.stripMetadata(response.Value).(map[string]interface{})
+	if entity != nil {
+		b.hideFieldsIn(entitySetName, entity)
+	}
+	return entity, nil
+}
+
+// previewDelete describes a delete and shows the entity that would be removed
+func (b *ODataMCPBridge) previewDelete(entitySetName string, entityType *models.EntityType) previewFunc {
+	return func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		key, err := entityKey(entityType, args)
+		if err != nil {
+			return nil, err
+		}
+		current, err := b.currentEntity(ctx, entitySetName, key)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"operation": constants.OpDelete,
+			"method":    constants.DELETE,
+			"url":       b.clientFor(ctx).EntityURL(entitySetName, key),
+			"key":       key,
+			"current":   current,
+		}, nil
+	}
+}
+
+// previewUpdate describes an update and diffs the new values against the current entity
+func (b *ODataMCPBridge) previewUpdate(entitySetName string, entityType *models.EntityType) previewFunc {
+	return func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		key, err := entityKey(entityType, args)
+		if err != nil {
+			return nil, err
+		}
+		method := constants.PUT
+		if m, ok := args["_method"].(string); ok && m != "" {
+			method = strings.ToUpper(m)
+		}
+		current, err := b.currentEntity(ctx, entitySetName, key)
+		if err != nil {
+			return nil, err
+		}
+
+		changes, unchanged := diffUpdate(current, args, key)
+		return map[string]interface{}{
+			"operation": constants.OpUpdate,
+			"method":    method,
+			"url":       b.clientFor(ctx).EntityURL(entitySetName, key),
+			"key":       key,
+			"changes":   changes,
+			"unchanged": unchanged,
+		}, nil
+	}
+}
+
+// diffUpdate splits the values of an update into changes, with their current
+// values, and properties that already have the new value
+func diffUpdate(current, args, key map[string]interface{}) (map[string]interface{}, []string) {
+	changes := make(map[string]interface{})
+	unchanged := make([]string, 0)
+	for name, value := range args {
+		if _, isKey := key[name]; isKey || name == "_method" || strings.HasPrefix(name, "$") {
+			continue
+		}
+		if old, exists := current[name]; exists && sameValue(old, value) {
+			unchanged = append(unchanged, name)
+			continue
+		}
+		changes[name] = map[string]interface{}{
+			"current": current[name],
+			"new":     value,
+		}
+	}
+	return changes, unchanged
+}
+
+// previewFunction describes a function import call that changes data
+func (b *ODataMCPBridge) previewFunction(functionName string, function *models.FunctionImport) previewFunc {
+	return func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"operation":  "function",
+			"method":     function.HTTPMethod,
+			"url":        b.clientFor(ctx).FunctionURL(functionName),
+			"parameters": args,
+		}, nil
+	}
+}
+
+// previewBatch lists the operations of a batch that contains updates or deletes
+func (b *ODataMCPBridge) previewBatch(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+	rawOps, _ := args["operations"].([]interface{})
+	operations := make([]map[string]interface{}, 0, len(rawOps))
+	needsConfirmation := false
+	created := batchCreates(batchAtomic(args))
+	for i, raw := range rawOps {
+		opArgs, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("operation %d: expected an object", i+1)
+		}
+		op, entitySetName, err := b.buildBatchOperation(opArgs, created)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i+1, err)
+		}
+		if op.Method == constants.POST && created != nil {
+			created[i+1] = entitySetName
+		}
+		if op.Method != constants.GET && op.Method != constants.POST {
+			needsConfirmation = true
+		}
+		entry := map[string]interface{}{"method": op.Method, "entity_set": op.Path}
+		if op.Key != nil {
+			entry["url"] = b.clientFor(ctx).EntityURL(op.Path, op.Key)
+			entry["key"] = op.Key
+		}
+		if op.Body != nil {
+			entry["body"] = op.Body
+		}
+		operations = append(operations, entry)
+	}
+	if !needsConfirmation {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"operation":  constants.OpBatch,
+		"operations": operations,
+	}, nil
+}
+
+// sameValue compares a current and a new property value. Services return
+// numbers as strings (Edm.Decimal "0.10"), so numbers are compared by value.
+func sameValue(current, value interface{}) bool {
+	if fmt.Sprint(current) == fmt.Sprint(value) {
+		return true
+	}
+	a, errA := strconv.ParseFloat(fmt.Sprint(current), 64)
+	b, errB := strconv.ParseFloat(fmt.Sprint(value), 64)
+	return errA == nil && errB == nil && a == b
+}
@@ -0,0 +1,56 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+)
+
+// dryRunParam is the tool argument that requests a dry run for a single call
+const dryRunParam = "$dry_run"
+
+// addDryRunParameter documents the per-call dry run argument in a tool schema
+func (b *ODataMCPBridge) addDryRunParameter(properties map[string]interface{}) {
+	properties[dryRunParam] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Return the HTTP request that would be sent (method, URL, headers, body) without sending it",
+	}
+}
+
+// isDryRun reports whether a tool call must only show its request, either
+// because the bridge runs with --dry-run or because the call asks for it
+func (b *ODataMCPBridge) isDryRun(args map[string]interface{}) bool {
+	if b.config.DryRun {
+		return true
+	}
+	dryRun, _ := args[dryRunParam].(bool)
+	return dryRun
+}
+
+// withDryRun wraps a tool handler so dry runs return the requests the handler
+// built instead of their results. The handler runs unchanged, with the same
+// validation and payload conversions, but the client never sends a request.
+func (b *ODataMCPBridge) withDryRun(handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		if !b.isDryRun(args) {
+			return handler(ctx, args)
+		}
+
+		ctx, recorder := client.WithDryRun(ctx)
+		if _, err := handler(ctx, args); err != nil {
+			return nil, err
+		}
+
+		response, err := json.Marshal(map[string]interface{}{
+			"dry_run":  true,
+			"requests": recorder.Requests(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(response), nil
+	}
+}
@@ -0,0 +1,130 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// MultiServiceBridge serves the tools of several OData services from one MCP server
+type MultiServiceBridge struct {
+	server   *mcp.Server
+	services []*ODataMCPBridge
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewMultiServiceBridge creates one bridge per service configuration, all
+// registering their tools on a shared MCP server. Each service keeps its own
+// client, authentication, filters and tool naming.
+func NewMultiServiceBridge(cfgs []*config.Config) (*MultiServiceBridge, error) {
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("no services configured")
+	}
+
+	mcpServer := mcp.NewServer(constants.MCPServerName, constants.MCPServerVersion)
+	multi := &MultiServiceBridge{server: mcpServer}
+
+	// Tool names must stay unique across services
+	owners := make(map[string]string)
+	for i, cfg := range cfgs {
+		name := cfg.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		service, err := newBridge(cfg, mcpServer)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+
+		for toolName := range service.tools {
+			if owner, exists := owners[toolName]; exists {
+				return nil, fmt.Errorf("tool %s is generated by services %s and %s, use distinct tool_postfix values", toolName, owner, name)
+			}
+			owners[toolName] = name
+		}
+
+		multi.services = append(multi.services, service)
+	}
+
+	return multi, nil
+}
+
+// GetServer returns the shared MCP server instance
+func (m *MultiServiceBridge) GetServer() *mcp.Server {
+	return m.server
+}
+
+// Services returns the bridges of the individual services
+func (m *MultiServiceBridge) Services() []*ODataMCPBridge {
+	return m.services
+}
+
+// Run starts the shared MCP server and the metadata refresh of every service
+func (m *MultiServiceBridge) Run() error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return fmt.Errorf("bridge is already running")
+	}
+	m.running = true
+	m.mu.Unlock()
+
+	for _, service := range m.services {
+		service.startRefresh()
+	}
+	return m.server.Run()
+}
+
+// Stop stops the shared MCP server
+func (m *MultiServiceBridge) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.running {
+		return
+	}
+
+	m.running = false
+	for _, service := range m.services {
+		close(service.stopChan)
+	}
+	m.server.Stop()
+}
+
+// Reload reloads the metadata of every service, keyed by service name in the result
+func (m *MultiServiceBridge) Reload(ctx context.Context) (map[string]*models.ReloadSummary, error) {
+	summaries := make(map[string]*models.ReloadSummary, len(m.services))
+	for i, service := range m.services {
+		name := service.config.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		summary, err := service.Reload(ctx)
+		if err != nil {
+			return summaries, fmt.Errorf("service %s: %w", name, err)
+		}
+		summaries[name] = summary
+	}
+	return summaries, nil
+}
+
+// GetTraceInfo returns the trace information of every service
+func (m *MultiServiceBridge) GetTraceInfo() ([]*models.TraceInfo, error) {
+	infos := make([]*models.TraceInfo, 0, len(m.services))
+	for _, service := range m.services {
+		info, err := service.GetTraceInfo()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,455 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// targetKeyParam is the tool argument holding the key of the entity to link or unlink
+const targetKeyParam = "target_key"
+
+// navigation is a navigation property of an entity set with its resolved target
+type navigation struct {
+	entitySet  string
+	entityType *models.EntityType
+	property   *models.NavigationProperty
+	target     string // Target entity set
+	targetType *models.EntityType
+}
+
+// name returns the entity name used in tool names, e.g. Orders_Items
+func (n navigation) name() string {
+	return fmt.Sprintf("%s_%s", n.entitySet, n.property.Name)
+}
+
+// generateNavigationTools creates tools that read the entities a navigation
+// property leads to and that create or delete associations ($links/$ref)
+func (b *ODataMCPBridge) generateNavigationTools(entitySetName string, entitySet *models.EntitySet, entityType *models.EntityType) {
+	for _, navProp := range entityType.NavigationProps {
+		nav, ok := b.resolveNavigation(entitySetName, entitySet, entityType, navProp)
+		if !ok || !b.shouldIncludeEntity(nav.target) {
+			continue
+		}
+
+		if b.isPermitted(entitySetName, constants.OpGet) {
+			if navProp.IsCollection && b.isPermitted(nav.target, constants.OpFilter) {
+				b.generateNavigationFilterTool(nav)
+			} else if !navProp.IsCollection && b.isPermitted(nav.target, constants.OpGet) {
+				b.generateNavigationGetTool(nav)
+			}
+		}
+
+		if entitySet.Updatable && b.isPermitted(entitySetName, constants.OpLink) {
+			b.generateLinkTool(nav)
+		}
+		if entitySet.Updatable && b.isPermitted(entitySetName, constants.OpUnlink) {
+			b.generateUnlinkTool(nav)
+		}
+	}
+}
+
+// resolveNavigation finds the entity set a navigation property leads to: the
+// one named by the metadata (v2 AssociationSet, v4 NavigationPropertyBinding),
+// or else the only entity set of the target type
+func (b *ODataMCPBridge) resolveNavigation(entitySetName string, entitySet *models.EntitySet, entityType *models.EntityType, navProp *models.NavigationProperty) (navigation, bool) {
+	meta := b.metadata.Load()
+	target := entitySet.NavigationTargets[navProp.Name]
+	if target == "" && navProp.TargetType != "" {
+		for name, candidate := range meta.EntitySets {
+			if candidate.EntityType != navProp.TargetType {
+				continue
+			}
+			if target != "" {
+				// Ambiguous without a binding
+				return navigation{}, false
+			}
+			target = name
+		}
+	}
+
+	targetSet, exists := meta.EntitySets[target]
+	if !exists {
+		return navigation{}, false
+	}
+	targetType, exists := meta.EntityTypes[targetSet.EntityType]
+	if !exists {
+		return navigation{}, false
+	}
+
+	return navigation{
+		entitySet:  entitySetName,
+		entityType: entityType,
+		property:   navProp,
+		target:     target,
+		targetType: targetType,
+	}, true
+}
+
+// relationships returns the relationship graph of the service: for each
+// entity set, the navigation properties and the entity sets they lead to
+func (b *ODataMCPBridge) relationships() []models.Relationship {
+	meta := b.metadata.Load()
+	names := make([]string, 0, len(meta.EntitySets))
+	for name := range meta.EntitySets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	graph := make([]models.Relationship, 0)
+	for _, name := range names {
+		entitySet := meta.EntitySets[name]
+		entityType, exists := meta.EntityTypes[entitySet.EntityType]
+		if !exists {
+			continue
+		}
+		for _, navProp := range entityType.NavigationProps {
+			nav, ok := b.resolveNavigation(name, entitySet, entityType, navProp)
+			if !ok {
+				continue
+			}
+			graph = append(graph, models.Relationship{
+				EntitySet:    name,
+				Navigation:   navProp.Name,
+				TargetSet:    nav.target,
+				TargetType:   nav.targetType.Name,
+				Multiplicity: navProp.Multiplicity,
+			})
+		}
+	}
+	return graph
+}
+
+// keyParameters returns the input schema properties and required names of the key of an entity type
+func (b *ODataMCPBridge) keyParameters(entityType *models.EntityType) (map[string]interface{}, []string) {
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	for _, keyProp := range entityType.KeyProperties {
+		for _, prop := range entityType.Properties {
+			if prop.Name == keyProp {
+				properties[keyProp] = b.keySchema(prop)
+				required = append(required, keyProp)
+				break
+			}
+		}
+	}
+	return properties, required
+}
+
+// addTargetKeyParameter adds the key of the linked entity as a nested object,
+// since its key properties may share names with the parent key
+func (b *ODataMCPBridge) addTargetKeyParameter(nav navigation, properties map[string]interface{}) {
+	targetProps, targetRequired := b.keyParameters(nav.targetType)
+	properties[targetKeyParam] = map[string]interface{}{
+		"type":        "object",
+		"description": fmt.Sprintf("Key of the %s entity", nav.target),
+		"properties":  targetProps,
+		"required":    targetRequired,
+	}
+}
+
+// generateNavigationFilterTool creates a tool listing the entities of a collection-valued navigation property
+func (b *ODataMCPBridge) generateNavigationFilterTool(nav navigation) {
+	opName := constants.GetToolOperationName(constants.OpFilter, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, nav.name())
+
+	description := b.entityDescription(nav.entitySet, fmt.Sprintf("List/filter the %s of a %s entity (%s entities) with OData query options", nav.property.Name, nav.entitySet, nav.target))
+
+	properties, required := b.keyParameters(nav.entityType)
+	for name, schema := range filterProperties() {
+		properties[name] = schema
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		},
+	}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleNavigationRead(ctx, nav, filterOptions(args), args)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   nav.entitySet,
+		Operation:   constants.OpFilter,
+	}
+}
+
+// generateNavigationGetTool creates a tool reading the entity of a single-valued navigation property
+func (b *ODataMCPBridge) generateNavigationGetTool(nav navigation) {
+	opName := constants.GetToolOperationName(constants.OpGet, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, nav.name())
+
+	description := b.entityDescription(nav.entitySet, fmt.Sprintf("Get the %s of a %s entity (%s entity)", nav.property.Name, nav.entitySet, nav.target))
+
+	properties, required := b.keyParameters(nav.entityType)
+	properties["$select"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Comma-separated list of properties to select",
+	}
+	properties["$expand"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Navigation properties to expand",
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		},
+	}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		options := make(map[string]string)
+		if selectParam, ok := args["$select"].(string); ok && selectParam != "" {
+			options[constants.QuerySelect] = selectParam
+		}
+		if expand, ok := args["$expand"].(string); ok && expand != "" {
+			options[constants.QueryExpand] = expand
+		}
+		return b.handleNavigationRead(ctx, nav, options, args)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   nav.entitySet,
+		Operation:   constants.OpGet,
+	}
+}
+
+// generateLinkTool creates a tool that associates an existing entity through a navigation property
+func (b *ODataMCPBridge) generateLinkTool(nav navigation) {
+	opName := constants.GetToolOperationName(constants.OpLink, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, nav.name())
+
+	description := fmt.Sprintf("Link a %s entity to a %s entity through %s", nav.target, nav.entitySet, nav.property.Name)
+	if !nav.property.IsCollection {
+		description = fmt.Sprintf("Set the %s of a %s entity to an existing %s entity", nav.property.Name, nav.entitySet, nav.target)
+	}
+	description = b.entityDescription(nav.entitySet, description)
+
+	properties, required := b.keyParameters(nav.entityType)
+	b.addTargetKeyParameter(nav, properties)
+	required = append(required, targetKeyParam)
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		},
+	}
+
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleLink(ctx, nav, args)
+	})
+	if b.config.ConfirmWrites {
+		handler = b.withConfirmation(toolName, b.previewLink(nav, constants.OpLink), handler)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   nav.entitySet,
+		Operation:   constants.OpLink,
+	}
+}
+
+// generateUnlinkTool creates a tool that removes an association without deleting the entities
+func (b *ODataMCPBridge) generateUnlinkTool(nav navigation) {
+	opName := constants.GetToolOperationName(constants.OpUnlink, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, nav.name())
+
+	description := b.entityDescription(nav.entitySet, fmt.Sprintf("Remove the link between a %s entity and its %s (%s) without deleting either entity", nav.entitySet, nav.property.Name, nav.target))
+
+	properties, required := b.keyParameters(nav.entityType)
+	if nav.property.IsCollection {
+		b.addTargetKeyParameter(nav, properties)
+		required = append(required, targetKeyParam)
+	}
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		},
+	}
+
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleUnlink(ctx, nav, args)
+	})
+	if b.config.ConfirmWrites {
+		handler = b.withConfirmation(toolName, b.previewLink(nav, constants.OpUnlink), handler)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   nav.entitySet,
+		Operation:   constants.OpUnlink,
+	}
+}
+
+// linkKeys extracts the key of the parent entity and, when needed, of the linked entity
+func linkKeys(nav navigation, args map[string]interface{}, needTarget bool) (map[string]interface{}, map[string]interface{}, error) {
+	key, err := entityKey(nav.entityType, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !needTarget {
+		return key, nil, nil
+	}
+
+	targetArgs, ok := args[targetKeyParam].(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("missing required parameter: %s", targetKeyParam)
+	}
+	targetKey, err := entityKey(nav.targetType, targetArgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", targetKeyParam, err)
+	}
+	return key, targetKey, nil
+}
+
+func (b *ODataMCPBridge) handleNavigationRead(ctx context.Context, nav navigation, options map[string]string, args map[string]interface{}) (interface{}, error) {
+	key, err := entityKey(nav.entityType, args)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.clientFor(ctx).GetNavigation(ctx, nav.entitySet, key, nav.property.Name, options, nav.property.IsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read navigation property %s: %w", nav.property.Name, err)
+	}
+
+	// Enhance response based on configuration
+	response = b.enhanceResponse(response, options)
+	b.hideFields(nav.target, response)
+
+	// Format response as JSON string
+	result, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+func (b *ODataMCPBridge) handleLink(ctx context.Context, nav navigation, args map[string]interface{}) (interface{}, error) {
+	key, targetKey, err := linkKeys(nav, args, true)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.clientFor(ctx).CreateLink(ctx, nav.entitySet, key, nav.property.Name, nav.target, targetKey, nav.property.IsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create link: %w", err)
+	}
+
+	// Format response as JSON string
+	result, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+func (b *ODataMCPBridge) handleUnlink(ctx context.Context, nav navigation, args map[string]interface{}) (interface{}, error) {
+	key, targetKey, err := linkKeys(nav, args, nav.property.IsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.clientFor(ctx).DeleteLink(ctx, nav.entitySet, key, nav.property.Name, targetKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete link: %w", err)
+	}
+
+	// Format response as JSON string
+	result, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+// previewLink describes a link or unlink and the entities it connects
+func (b *ODataMCPBridge) previewLink(nav navigation, operation string) previewFunc {
+	return func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		needTarget := operation == constants.OpLink || nav.property.IsCollection
+		key, targetKey, err := linkKeys(nav, args, needTarget)
+		if err != nil {
+			return nil, err
+		}
+
+		method := constants.DELETE
+		urlKey := targetKey
+		if operation == constants.OpLink {
+			method = constants.PUT
+			if nav.property.IsCollection {
+				method = constants.POST
+			}
+			urlKey = nil
+		}
+
+		preview := map[string]interface{}{
+			"operation":  operation,
+			"method":     method,
+			"url":        b.clientFor(ctx).LinkURL(nav.entitySet, key, nav.property.Name, urlKey),
+			"key":        key,
+			"navigation": nav.property.Name,
+		}
+		if targetKey != nil {
+			preview[targetKeyParam] = targetKey
+			preview["target"] = b.clientFor(ctx).EntityURL(nav.target, targetKey)
+		}
+		return preview, nil
+	}
+}
@@ -0,0 +1,145 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// isOperationAllowed checks if an operation may be exposed for an entity set, based on
+// the service capabilities, read-only mode, operation rules and entity overrides
+func (b *ODataMCPBridge) isOperationAllowed(entitySetName string, entitySet *models.EntitySet, operation string) bool {
+	switch operation {
+	case constants.OpSearch:
+		if !entitySet.Searchable {
+			return false
+		}
+	case constants.OpCount:
+		if !entitySet.Countable {
+			return false
+		}
+	case constants.OpCreate:
+		if !entitySet.Creatable {
+			return false
+		}
+	case constants.OpUpdate:
+		if !entitySet.Updatable {
+			return false
+		}
+	case constants.OpDelete:
+		if !entitySet.Deletable {
+			return false
+		}
+	}
+	return b.isPermitted(entitySetName, operation)
+}
+
+// isPermitted applies read-only mode, operation rules and entity overrides to an
+// operation on an entity set or singleton
+func (b *ODataMCPBridge) isPermitted(name, operation string) bool {
+	write := false
+	switch operation {
+	case constants.OpCreate, constants.OpUpdate, constants.OpDelete, constants.OpLink, constants.OpUnlink:
+		write = true
+	}
+
+	if write && b.config.ReadOnly {
+		return false
+	}
+	if override, _ := b.config.EntityOverride(name); write && override.ReadOnly {
+		return false
+	}
+
+	for _, rule := range b.config.OperationRules {
+		if b.matchesPattern(name, rule.Entity) && !rule.Permits(operation) {
+			return false
+		}
+	}
+	return true
+}
+
+// isFunctionAllowed checks if a function import may be exposed. Function imports
+// invoked with POST can change data and are suppressed in read-only mode.
+func (b *ODataMCPBridge) isFunctionAllowed(function *models.FunctionImport) bool {
+	if !strings.EqualFold(function.HTTPMethod, constants.POST) {
+		return true
+	}
+	return !b.config.ReadOnly && !b.config.NoPostFunctions
+}
+
+// entityDescription appends the configured description of an entity set to a tool description
+func (b *ODataMCPBridge) entityDescription(entitySetName, description string) string {
+	if annotated := b.annotatedDescription(entitySetName); annotated != "" {
+		description = fmt.Sprintf("%s. %s", description, annotated)
+	}
+	if override, ok := b.config.EntityOverride(entitySetName); ok && override.Description != "" {
+		return fmt.Sprintf("%s. %s", description, override.Description)
+	}
+	return description
+}
+
+// propertyDescription returns the configured description of a property or the fallback
+func (b *ODataMCPBridge) propertyDescription(entitySetName, property, fallback string) string {
+	if override, ok := b.config.EntityOverride(entitySetName); ok {
+		if description, ok := override.FieldDescription(property); ok {
+			return description
+		}
+	}
+	return fallback
+}
+
+// isHiddenField checks if a non-key property is hidden by the entity overrides
+func (b *ODataMCPBridge) isHiddenField(entitySetName string, prop *models.EntityProperty) bool {
+	if prop.IsKey {
+		return false
+	}
+	override, ok := b.config.EntityOverride(entitySetName)
+	return ok && override.IsHidden(prop.Name)
+}
+
+// checkHiddenFields rejects payloads that write hidden properties
+func (b *ODataMCPBridge) checkHiddenFields(entitySetName string, data map[string]interface{}) error {
+	override, ok := b.config.EntityOverride(entitySetName)
+	if !ok {
+		return nil
+	}
+	for name := range data {
+		if override.IsHidden(name) {
+			return fmt.Errorf("property %s of %s is not writable", name, entitySetName)
+		}
+	}
+	return nil
+}
+
+// hideFields removes hidden properties from the entities of a response
+func (b *ODataMCPBridge) hideFields(entitySetName string, response *models.ODataResponse) {
+	if response == nil {
+		return
+	}
+	b.hideFieldsIn(entitySetName, response.Value)
+	b.hideFieldsIn(entitySetName, response.Results)
+}
+
+// hideFieldsIn removes hidden properties from an entity or a list of entities
+func (b *ODataMCPBridge) hideFieldsIn(entitySetName string, data interface{}) {
+	override, ok := b.config.EntityOverride(entitySetName)
+	if !ok || len(override.HiddenFields) == 0 {
+		return
+	}
+
+	entities, isList := data.([]interface{})
+	if !isList {
+		entities = []interface{}{data}
+	}
+	for _, entity := range entities {
+		if fields, ok := entity.(map[string]interface{}); ok {
+			for name := range fields {
+				if override.IsHidden(name) {
+					delete(fields, name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// Reload fetches the metadata again and regenerates the tools. New and
+// changed tools are registered, tools that are no longer generated are
+// removed, and clients are sent notifications/tools/list_changed when the
+// tool list changed.
+func (b *ODataMCPBridge) Reload(ctx context.Context) (*models.ReloadSummary, error) {
+	b.reloadMu.Lock()
+	defer b.reloadMu.Unlock()
+
+	metadata, err := b.loadMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember the current definitions to tell changed tools from unchanged ones
+	previous := make(map[string]*mcp.Tool)
+	b.mu.RLock()
+	for _, tool := range b.server.GetTools() {
+		if _, ok := b.tools[tool.Name]; ok {
+			previous[tool.Name] = tool
+		}
+	}
+	b.mu.RUnlock()
+
+	// Generate into staged state, the server and the old state are only
+	// replaced once generation succeeded
+	b.mu.Lock()
+	oldMetadata, oldTools := b.metadata.Load(), b.tools
+	b.metadata.Store(metadata)
+	b.tools = make(map[string]*models.ToolInfo)
+	b.staging, b.pending = true, nil
+	err = b.generateTools()
+	pending := b.pending
+	b.staging, b.pending = false, nil
+	if err != nil {
+		b.metadata.Store(oldMetadata)
+		b.tools = oldTools
+		b.mu.Unlock()
+		return nil, fmt.Errorf("failed to generate tools: %w", err)
+	}
+	for _, p := range pending {
+		b.server.AddTool(p.tool, p.handler)
+	}
+	b.generateResources()
+	toolCount := len(b.tools)
+	current := make(map[string]bool, toolCount)
+	for name := range b.tools {
+		current[name] = true
+	}
+	b.mu.Unlock()
+
+	summary := &models.ReloadSummary{TotalTools: toolCount}
+	summary.AddedEntitySets, summary.RemovedEntitySets = diffKeys(oldMetadata.EntitySets, metadata.EntitySets)
+	summary.AddedFunctionImports, summary.RemovedFunctionImports = diffKeys(oldMetadata.FunctionImports, metadata.FunctionImports)
+
+	definitions := make(map[string]*mcp.Tool)
+	for _, tool := range b.server.GetTools() {
+		definitions[tool.Name] = tool
+	}
+	for name := range current {
+		old, existed := previous[name]
+		switch {
+		case !existed:
+			summary.AddedTools = append(summary.AddedTools, name)
+		case !sameTool(old, definitions[name]):
+			summary.ChangedTools = append(summary.ChangedTools, name)
+		}
+	}
+	for name := range previous {
+		if !current[name] {
+			b.server.RemoveTool(name)
+			summary.RemovedTools = append(summary.RemovedTools, name)
+		}
+	}
+	sort.Strings(summary.AddedTools)
+	sort.Strings(summary.ChangedTools)
+	sort.Strings(summary.RemovedTools)
+
+	if len(summary.AddedTools)+len(summary.ChangedTools)+len(summary.RemovedTools) > 0 {
+		if err := b.server.NotifyToolsListChanged(); err != nil && b.config.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to send tools/list_changed notification: %v\n", err)
+		}
+	}
+	if b.config.Verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Reloaded metadata: %d tools added, %d changed, %d removed\n",
+			len(summary.AddedTools), len(summary.ChangedTools), len(summary.RemovedTools))
+	}
+	return summary, nil
+}
+
+// sameTool reports whether two tool definitions are identical
+func sameTool(a, b *mcp.Tool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Description == b.Description && reflect.DeepEqual(a.InputSchema, b.InputSchema)
+}
+
+// diffKeys returns the sorted names that were added to and removed from a map
+func diffKeys[V any](old, current map[string]V) ([]string, []string) {
+	var added, removed []string
+	for name := range current {
+		if _, ok := old[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for name := range old {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// startRefresh reloads the metadata every --metadata-refresh interval until the bridge stops
+func (b *ODataMCPBridge) startRefresh() {
+	if b.config.MetadataRefresh <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(b.config.MetadataRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.stopChan:
+				return
+			case <-ticker.C:
+				if _, err := b.Reload(context.Background()); err != nil {
+					fmt.Fprintf(os.Stderr, "WARNING: metadata refresh failed: %v\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// generateReloadTool creates a tool that reloads the metadata on demand (--reload-tool)
+func (b *ODataMCPBridge) generateReloadTool() {
+	toolName := b.formatToolName("odata_reload_metadata", "")
+	description := "Reload the OData service metadata and update the tool list with new, changed and removed entity sets, properties and functions"
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		summary, err := b.Reload(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reload metadata: %w", err)
+		}
+		result, err := json.Marshal(summary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(result), nil
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+	}
+}
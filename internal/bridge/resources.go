@@ -0,0 +1,295 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+)
+
+// contentTypeJSONSchema is the MIME type of the entity type schema resources
+const contentTypeJSONSchema = "application/schema+json"
+
+// resourceRoot returns the odata://{service}/ prefix of the resource URIs of
+// the service, named by its configured name or else its service ID
+func (b *ODataMCPBridge) resourceRoot() string {
+	service := b.config.Name
+	if service == "" {
+		service = constants.FormatServiceID(b.config.ServiceURL)
+	}
+	return fmt.Sprintf("odata://%s/", url.PathEscape(service))
+}
+
+// generateResources registers the $metadata document, the service document
+// and one JSON Schema per entity type as resources, and a template that
+// addresses single entities. Resources of entity types that are gone after a
+// reload are removed.
+func (b *ODataMCPBridge) generateResources() {
+	root := b.resourceRoot()
+	service := strings.TrimSuffix(strings.TrimPrefix(root, "odata://"), "/")
+	current := make(map[string]bool)
+
+	add := func(resource *mcp.Resource, handler mcp.ResourceHandler) {
+		b.server.AddResource(resource, handler)
+		current[resource.URI] = true
+	}
+
+	add(&mcp.Resource{
+		URI:         root + "$metadata",
+		Name:        fmt.Sprintf("%s $metadata", service),
+		Description: "EDMX metadata document of the OData service",
+		MimeType:    constants.ContentTypeXML,
+	}, b.readMetadataResource)
+
+	add(&mcp.Resource{
+		URI:         root,
+		Name:        fmt.Sprintf("%s service document", service),
+		Description: "Service document listing the entity sets, singletons and function imports of the OData service",
+		MimeType:    constants.ContentTypeJSON,
+	}, b.readServiceDocumentResource)
+
+	typeSets := b.resourceEntityTypes()
+	typeNames := make([]string, 0, len(typeSets))
+	for typeName := range typeSets {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		typeName, entitySetName := typeName, typeSets[typeName]
+		add(&mcp.Resource{
+			URI:         root + "$schema/" + typeName,
+			Name:        fmt.Sprintf("%s %s schema", service, typeName),
+			Description: fmt.Sprintf("JSON Schema of the %s entity type", typeName),
+			MimeType:    contentTypeJSONSchema,
+		}, func(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+			return b.readSchemaResource(uri, typeName, entitySetName)
+		})
+	}
+
+	for _, resource := range b.server.GetResources() {
+		if strings.HasPrefix(resource.URI, root) && !current[resource.URI] {
+			b.server.RemoveResource(resource.URI)
+		}
+	}
+
+	b.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: root + "{EntitySet}({key})",
+		Name:        fmt.Sprintf("%s entity", service),
+		Description: "A single entity by entity set and key predicate, e.g. Orders('1') or Items(ID='1',Pos=2)",
+		MimeType:    constants.ContentTypeJSON,
+	}, b.readEntityResource)
+}
+
+// resourceEntityTypes maps the entity types of the included entity sets and
+// singletons to the first entity set or singleton using them, whose overrides
+// apply to the schema
+func (b *ODataMCPBridge) resourceEntityTypes() map[string]string {
+	meta := b.metadata.Load()
+	names := make([]string, 0, len(meta.EntitySets)+len(meta.Singletons))
+	types := make(map[string]string)
+	for name, entitySet := range meta.EntitySets {
+		names = append(names, name)
+		types[name] = entitySet.EntityType
+	}
+	for name, singleton := range meta.Singletons {
+		names = append(names, name)
+		types[name] = singleton.EntityType
+	}
+	sort.Strings(names)
+
+	typeSets := make(map[string]string)
+	for _, name := range names {
+		typeName := types[name]
+		if _, exists := meta.EntityTypes[typeName]; !exists || !b.shouldIncludeEntity(name) {
+			continue
+		}
+		if _, seen := typeSets[typeName]; !seen {
+			typeSets[typeName] = name
+		}
+	}
+	return typeSets
+}
+
+// readMetadataResource returns the $metadata document, read from --metadata-file when set
+func (b *ODataMCPBridge) readMetadataResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	var data []byte
+	var err error
+	if b.config.MetadataFile != "" {
+		data, err = os.ReadFile(b.config.MetadataFile)
+	} else {
+		data, err = b.clientFor(ctx).GetRawMetadata(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	return &mcp.ResourceContents{URI: uri, MimeType: constants.ContentTypeXML, Text: string(data)}, nil
+}
+
+// readServiceDocumentResource returns the service document
+func (b *ODataMCPBridge) readServiceDocumentResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	data, err := b.clientFor(ctx).GetRawServiceDocument(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch service document: %w", err)
+	}
+	return &mcp.ResourceContents{URI: uri, MimeType: constants.ContentTypeJSON, Text: string(data)}, nil
+}
+
+// readSchemaResource returns the JSON Schema of an entity type. Hidden fields
+// and property descriptions follow the overrides of entitySetName.
+func (b *ODataMCPBridge) readSchemaResource(uri, typeName, entitySetName string) (*mcp.ResourceContents, error) {
+	meta := b.metadata.Load()
+	entityType, ok := meta.EntityTypes[typeName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+
+	properties := make(map[string]interface{})
+	for _, prop := range entityType.Properties {
+		if b.isHiddenField(entitySetName, prop) {
+			continue
+		}
+		if prop.IsKey {
+			properties[prop.Name] = b.keySchema(prop)
+		} else {
+			properties[prop.Name] = b.propertySchema(entitySetName, prop)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      typeName,
+		"type":       "object",
+		"properties": properties,
+		"required":   entityType.KeyProperties,
+	}
+	if entityType.Description != nil && *entityType.Description != "" {
+		schema["description"] = *entityType.Description
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format schema: %w", err)
+	}
+	return &mcp.ResourceContents{URI: uri, MimeType: contentTypeJSONSchema, Text: string(data)}, nil
+}
+
+// readEntityResource returns the entity addressed by odata://{service}/{EntitySet}({key}).
+// Only entity sets with a get tool can be read.
+func (b *ODataMCPBridge) readEntityResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	meta := b.metadata.Load()
+	notFound := fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+
+	path, err := url.PathUnescape(strings.TrimPrefix(uri, b.resourceRoot()))
+	if err != nil {
+		return nil, notFound
+	}
+	open := strings.Index(path, "(")
+	if open <= 0 || !strings.HasSuffix(path, ")") {
+		return nil, notFound
+	}
+	entitySetName, predicate := path[:open], path[open+1:len(path)-1]
+
+	entitySet, ok := meta.EntitySets[entitySetName]
+	if !ok || !b.shouldIncludeEntity(entitySetName) || !b.isOperationAllowed(entitySetName, entitySet, constants.OpGet) {
+		return nil, notFound
+	}
+	entityType, ok := meta.EntityTypes[entitySet.EntityType]
+	if !ok {
+		return nil, notFound
+	}
+
+	key, err := parseKeyPredicate(predicate, entityType.KeyProperties)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key of %s: %w", entitySetName, err)
+	}
+
+	response, err := b.clientFor(ctx).GetEntity(ctx, entitySetName, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+	b.hideFields(entitySetName, response)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	return &mcp.ResourceContents{URI: uri, MimeType: constants.ContentTypeJSON, Text: string(data)}, nil
+}
+
+// parseKeyPredicate parses an OData key predicate such as '1', ID='1' or
+// ID='1',Pos=2 into key values. A value without name is only accepted for
+// single-property keys.
+func parseKeyPredicate(predicate string, keyProperties []string) (map[string]interface{}, error) {
+	parts := splitKeyPredicate(predicate)
+	key := make(map[string]interface{}, len(parts))
+
+	for _, part := range parts {
+		name, value := "", part
+		if eq, quote := strings.Index(part, "="), strings.Index(part, "'"); eq > 0 && (quote < 0 || eq < quote) {
+			name, value = strings.TrimSpace(part[:eq]), part[eq+1:]
+		} else if len(parts) == 1 && len(keyProperties) == 1 {
+			name = keyProperties[0]
+		}
+
+		known := false
+		for _, keyProp := range keyProperties {
+			if keyProp == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown key property in %q", part)
+		}
+		key[name] = parseKeyLiteral(strings.TrimSpace(value))
+	}
+
+	for _, keyProp := range keyProperties {
+		if _, exists := key[keyProp]; !exists {
+			return nil, fmt.Errorf("missing required key property: %s", keyProp)
+		}
+	}
+	return key, nil
+}
+
+// splitKeyPredicate splits a key predicate at the commas outside of string literals
+func splitKeyPredicate(predicate string) []string {
+	var parts []string
+	inString := false
+	start := 0
+	for i, r := range predicate {
+		switch {
+		case r == '\'':
+			inString = !inString
+		case r == ',' && !inString:
+			parts = append(parts, predicate[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, predicate[start:])
+}
+
+// parseKeyLiteral converts an OData literal to the value formatKeyValue renders back
+func parseKeyLiteral(literal string) interface{} {
+	if len(literal) >= 2 && strings.HasPrefix(literal, "'") && strings.HasSuffix(literal, "'") {
+		return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")
+	}
+	if literal == "true" || literal == "false" {
+		return literal == "true"
+	}
+	if i, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f
+	}
+	return literal
+}
@@ -0,0 +1,232 @@
+package bridge
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// integerRanges are the value ranges of the EDM integer types that fit a JSON number exactly
+var integerRanges = map[string][2]int64{
+	"Edm.Byte":  {0, math.MaxUint8},
+	"Edm.SByte": {math.MinInt8, math.MaxInt8},
+	"Edm.Int16": {math.MinInt16, math.MaxInt16},
+	"Edm.Int32": {math.MinInt32, math.MaxInt32},
+}
+
+// typeFormats are the JSON Schema formats of EDM string types
+var typeFormats = map[string]string{
+	"Edm.Guid":           "uuid",
+	"Edm.DateTime":       "date-time",
+	"Edm.DateTimeOffset": "date-time",
+	"Edm.Date":           "date",
+	"Edm.TimeOfDay":      "time",
+	"Edm.Time":           "duration",
+	"Edm.Duration":       "duration",
+	"Edm.Binary":         "byte",
+}
+
+// maxTypeDepth limits how deep nested complex types are expanded, since a
+// complex type may contain itself
+const maxTypeDepth = 5
+
+// schemaForType returns the JSON Schema of an EDM type: the format of string
+// types, the value range of small integer types, nested objects for complex
+// types, enums for enum types and arrays for collections
+func (b *ODataMCPBridge) schemaForType(odataType string) map[string]interface{} {
+	return b.typeSchema(odataType, 0)
+}
+
+func (b *ODataMCPBridge) typeSchema(odataType string, depth int) map[string]interface{} {
+	if itemType, ok := collectionItemType(odataType); ok {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": b.typeSchema(itemType, depth),
+		}
+	}
+	if enumType := b.enumType(odataType); enumType != nil {
+		return enumSchema(enumType)
+	}
+	if complexType := b.complexType(odataType); complexType != nil {
+		return b.complexSchema(complexType, depth)
+	}
+
+	schema := map[string]interface{}{
+		"type": b.getJSONSchemaType(odataType),
+	}
+	if format, ok := typeFormats[odataType]; ok {
+		schema["format"] = format
+	}
+	if bounds, ok := integerRanges[odataType]; ok {
+		schema["minimum"] = bounds[0]
+		schema["maximum"] = bounds[1]
+	}
+	if odataType == "Edm.Decimal" {
+		// Decimals are sent as strings to keep their precision, numbers are converted
+		schema["type"] = []interface{}{"number", "string"}
+	}
+	return schema
+}
+
+// complexSchema describes a complex type as an object with its properties
+func (b *ODataMCPBridge) complexSchema(complexType *models.ComplexType, depth int) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object"}
+	if depth >= maxTypeDepth {
+		return schema
+	}
+
+	properties := make(map[string]interface{}, len(complexType.Properties))
+	required := make([]string, 0)
+	for _, prop := range complexType.Properties {
+		propSchema := b.facetTypeSchema(prop, depth+1)
+		propSchema["description"] = labelOr(prop, fmt.Sprintf("Property: %s", prop.Name))
+		if prop.Nullable {
+			allowNull(propSchema)
+		} else {
+			required = append(required, prop.Name)
+		}
+		properties[prop.Name] = propSchema
+	}
+	schema["properties"] = properties
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// enumSchema lists the member names of an enum type. Flags enums take a list
+// of members, which is sent as the comma-separated OData value.
+func enumSchema(enumType *models.EnumType) map[string]interface{} {
+	names := make([]string, 0, len(enumType.Members))
+	for _, member := range enumType.Members {
+		names = append(names, member.Name)
+	}
+	member := map[string]interface{}{
+		"type": "string",
+		"enum": names,
+	}
+	if !enumType.IsFlags {
+		return member
+	}
+	return map[string]interface{}{
+		"type":        "array",
+		"items":       member,
+		"uniqueItems": true,
+	}
+}
+
+// keySchema returns the JSON Schema of a key property
+func (b *ODataMCPBridge) keySchema(prop *models.EntityProperty) map[string]interface{} {
+	schema := b.facetTypeSchema(prop, 0)
+	description := fmt.Sprintf("Key property: %s", prop.Name)
+	if prop.Description != nil && *prop.Description != "" {
+		description = fmt.Sprintf("%s (%s)", description, *prop.Description)
+	}
+	schema["description"] = propertyNotes(prop, description)
+	return schema
+}
+
+// propertySchema returns the JSON Schema of a non-key property for create and
+// update tools. Nullable properties also accept null.
+func (b *ODataMCPBridge) propertySchema(entitySetName string, prop *models.EntityProperty) map[string]interface{} {
+	schema := b.facetTypeSchema(prop, 0)
+	schema["description"] = b.propertyDescription(entitySetName, prop.Name, propertyNotes(prop, labelOr(prop, fmt.Sprintf("Property: %s", prop.Name))))
+	if prop.Nullable {
+		allowNull(schema)
+	}
+	return schema
+}
+
+// facetTypeSchema applies the MaxLength, Precision and Scale facets of a
+// property to its type schema. Facets of collections apply to their items.
+func (b *ODataMCPBridge) facetTypeSchema(prop *models.EntityProperty, depth int) map[string]interface{} {
+	schema := b.typeSchema(prop.Type, depth)
+
+	target, valueType := schema, prop.Type
+	if itemType, ok := collectionItemType(prop.Type); ok {
+		target, _ = schema["items"].(map[string]interface{})
+		valueType = itemType
+	}
+	if prop.MaxLength > 0 && (valueType == "Edm.String" || valueType == "") {
+		target["maxLength"] = prop.MaxLength
+	}
+	if valueType == "Edm.Decimal" && prop.Precision > 0 {
+		target["pattern"] = decimalPattern(prop.Precision, prop.Scale)
+	}
+	return schema
+}
+
+// allowNull adds null to the allowed types of a schema
+func allowNull(schema map[string]interface{}) {
+	switch t := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{t, "null"}
+	case []interface{}:
+		schema["type"] = append(t, "null")
+	}
+}
+
+// labelOr returns the label of a property (sap:label) or the fallback
+func labelOr(prop *models.EntityProperty, fallback string) string {
+	if prop.Description != nil && *prop.Description != "" {
+		return *prop.Description
+	}
+	return fallback
+}
+
+// collectionItemType returns the item type of a Collection(...) type
+func collectionItemType(odataType string) (string, bool) {
+	if strings.HasPrefix(odataType, "Collection(") && strings.HasSuffix(odataType, ")") {
+		return odataType[len("Collection(") : len(odataType)-1], true
+	}
+	return "", false
+}
+
+// complexType looks up a complex type by qualified or unqualified name
+func (b *ODataMCPBridge) complexType(odataType string) *models.ComplexType {
+	meta := b.metadata.Load()
+	if meta == nil || meta.ComplexTypes == nil || strings.HasPrefix(odataType, "Edm.") {
+		return nil
+	}
+	if t, ok := meta.ComplexTypes[odataType]; ok {
+		return t
+	}
+	return meta.ComplexTypes[unqualifiedName(odataType)]
+}
+
+// enumType looks up an enum type by qualified or unqualified name
+func (b *ODataMCPBridge) enumType(odataType string) *models.EnumType {
+	meta := b.metadata.Load()
+	if meta == nil || meta.EnumTypes == nil || strings.HasPrefix(odataType, "Edm.") {
+		return nil
+	}
+	if t, ok := meta.EnumTypes[odataType]; ok {
+		return t
+	}
+	return meta.EnumTypes[unqualifiedName(odataType)]
+}
+
+// unqualifiedName strips the namespace from a type name
+func unqualifiedName(typeName string) string {
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		return typeName[i+1:]
+	}
+	return typeName
+}
+
+// decimalPattern matches decimal strings with at most precision digits, of
+// which at most scale follow the decimal point. A scale of -1 is variable.
+func decimalPattern(precision, scale int) string {
+	switch {
+	case scale < 0:
+		return fmt.Sprintf(`^-?\d{1,%d}(\.\d+)?$`, precision)
+	case scale == 0:
+		return fmt.Sprintf(`^-?\d{1,%d}$`, precision)
+	case scale >= precision:
+		return fmt.Sprintf(`^-?(0|0?\.\d{1,%d})$`, precision)
+	default:
+		return fmt.Sprintf(`^-?\d{1,%d}(\.\d{1,%d})?$`, precision-scale, scale)
+	}
+}
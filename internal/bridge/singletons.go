@@ -0,0 +1,229 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/models"
+	"github.com/zmcp/odata-mcp/internal/utils"
+)
+
+// generateSingletonTools creates the get and update tools of an OData v4
+// singleton. Singletons are addressed by name, so the tools take no key.
+func (b *ODataMCPBridge) generateSingletonTools(name string, singleton *models.Singleton) {
+	meta := b.metadata.Load()
+	entityType, exists := meta.EntityTypes[singleton.EntityType]
+	if !exists {
+		if b.config.Verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Entity type not found for singleton %s: %s\n", name, singleton.EntityType)
+		}
+		return
+	}
+
+	if b.isPermitted(name, constants.OpGet) {
+		b.generateSingletonGetTool(name, entityType)
+	}
+	if singleton.Updatable && b.isPermitted(name, constants.OpUpdate) {
+		b.generateSingletonUpdateTool(name, entityType)
+	}
+}
+
+// generateSingletonGetTool creates a get tool for a singleton
+func (b *ODataMCPBridge) generateSingletonGetTool(name string, entityType *models.EntityType) {
+	opName := constants.GetToolOperationName(constants.OpGet, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, name)
+
+	description := b.entityDescription(name, fmt.Sprintf("Get the %s singleton", name))
+
+	properties := map[string]interface{}{
+		"$select": map[string]interface{}{
+			"type":        "string",
+			"description": "Comma-separated list of properties to select",
+		},
+		"$expand": map[string]interface{}{
+			"type":        "string",
+			"description": "Navigation properties to expand",
+		},
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		},
+	}
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleSingletonGet(ctx, name, args)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   name,
+		Operation:   constants.OpGet,
+	}
+}
+
+// generateSingletonUpdateTool creates an update tool for a singleton
+func (b *ODataMCPBridge) generateSingletonUpdateTool(name string, entityType *models.EntityType) {
+	opName := constants.GetToolOperationName(constants.OpUpdate, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, name)
+
+	description := b.entityDescription(name, fmt.Sprintf("Update the %s singleton", name))
+
+	// Key properties identify entities in a set, a singleton is addressed by name
+	properties := make(map[string]interface{})
+	for _, prop := range entityType.Properties {
+		if !prop.IsKey && prop.Updatable && !b.isHiddenField(name, prop) {
+			properties[prop.Name] = b.propertySchema(name, prop)
+		}
+	}
+
+	// OData v4 services update singletons with PATCH, PUT replaces them
+	properties["_method"] = map[string]interface{}{
+		"type":        "string",
+		"description": "HTTP method to use (PUT, PATCH, or MERGE)",
+		"enum":        []string{"PUT", "PATCH", "MERGE"},
+		"default":     "PATCH",
+	}
+	b.addDryRunParameter(properties)
+
+	if b.config.ConfirmWrites {
+		description = confirmationDescription(description)
+		b.addConfirmationParameter(properties)
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		},
+	}
+
+	handler := b.withDryRun(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleSingletonUpdate(ctx, name, entityType, args)
+	})
+	if b.config.ConfirmWrites {
+		handler = b.withConfirmation(toolName, b.previewSingletonUpdate(name), handler)
+	}
+
+	b.addTool(tool, handler)
+
+	// Track tool info
+	b.tools[toolName] = &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   name,
+		Operation:   constants.OpUpdate,
+	}
+}
+
+func (b *ODataMCPBridge) handleSingletonGet(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	// Build query options for expand/select
+	options := make(map[string]string)
+	if selectParam, ok := args["$select"].(string); ok && selectParam != "" {
+		options[constants.QuerySelect] = selectParam
+	}
+	if expand, ok := args["$expand"].(string); ok && expand != "" {
+		options[constants.QueryExpand] = expand
+	}
+
+	response, err := b.clientFor(ctx).GetSingleton(ctx, name, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get singleton: %w", err)
+	}
+	b.hideFields(name, response)
+
+	// Format response as JSON string
+	result, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+func (b *ODataMCPBridge) handleSingletonUpdate(ctx context.Context, name string, entityType *models.EntityType, args map[string]interface{}) (interface{}, error) {
+	method := constants.PATCH
+	updateData := make(map[string]interface{})
+	for k, v := range args {
+		if k == "_method" {
+			if m, ok := v.(string); ok && m != "" {
+				method = m
+			}
+			continue
+		}
+		if !strings.HasPrefix(k, "$") {
+			updateData[k] = v
+		}
+	}
+	if err := b.checkHiddenFields(name, updateData); err != nil {
+		return nil, err
+	}
+	updateData = b.serializeStructured(entityType.Properties, updateData)
+
+	// Convert numeric fields to strings for SAP OData v2 compatibility
+	updateData = utils.ConvertNumericsInMap(updateData)
+
+	// Convert date fields to OData legacy format if needed
+	if b.config.LegacyDates {
+		updateData = utils.ConvertDatesInMap(updateData, false) // false = convert ISO to legacy
+	}
+
+	response, err := b.clientFor(ctx).UpdateSingleton(ctx, name, updateData, method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update singleton: %w", err)
+	}
+
+	// Enhance response (includes date conversion if enabled)
+	response = b.enhanceResponse(response, make(map[string]string))
+	b.hideFields(name, response)
+
+	// Format response as JSON string
+	result, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+// previewSingletonUpdate describes a singleton update and diffs the new values against the current singleton
+func (b *ODataMCPBridge) previewSingletonUpdate(name string) previewFunc {
+	return func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+		method := constants.PATCH
+		if m, ok := args["_method"].(string); ok && m != "" {
+			method = strings.ToUpper(m)
+		}
+		response, err := b.clientFor(ctx).GetSingleton(ctx, name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read singleton for preview: %w", err)
+		}
+		current, _ := b.stripMetadata(response.Value).(map[string]interface{})
+		if current != nil {
+			b.hideFieldsIn(name, current)
+		}
+
+		changes, unchanged := diffUpdate(current, args, nil)
+		return map[string]interface{}{
+			"operation": constants.OpUpdate,
+			"method":    method,
+			"url":       b.clientFor(ctx).SingletonURL(name),
+			"changes":   changes,
+			"unchanged": unchanged,
+		}, nil
+	}
+}
@@ -0,0 +1,85 @@
+package bridge
+
+import (
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// serializeStructured converts the enum, complex and collection values of an
+// entity payload to their OData JSON form. Flags enums given as a list of
+// members become a comma-separated string, enum members given by value become
+// their names, and complex values and collections are converted recursively.
+// Properties without metadata are passed through unchanged.
+func (b *ODataMCPBridge) serializeStructured(properties []*models.EntityProperty, data map[string]interface{}) map[string]interface{} {
+	return b.serializeProperties(properties, data, 0)
+}
+
+func (b *ODataMCPBridge) serializeProperties(properties []*models.EntityProperty, data map[string]interface{}, depth int) map[string]interface{} {
+	types := make(map[string]string, len(properties))
+	for _, prop := range properties {
+		types[prop.Name] = prop.Type
+	}
+
+	result := make(map[string]interface{}, len(data))
+	for name, value := range data {
+		if odataType, ok := types[name]; ok {
+			value = b.serializeValue(odataType, value, depth)
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// serializeValue converts a single value of the given EDM type
+func (b *ODataMCPBridge) serializeValue(odataType string, value interface{}, depth int) interface{} {
+	if value == nil || depth > maxTypeDepth {
+		return value
+	}
+
+	if itemType, ok := collectionItemType(odataType); ok {
+		items, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			result[i] = b.serializeValue(itemType, item, depth)
+		}
+		return result
+	}
+
+	if enumType := b.enumType(odataType); enumType != nil {
+		return serializeEnum(enumType, value)
+	}
+
+	if complexType := b.complexType(odataType); complexType != nil {
+		if object, ok := value.(map[string]interface{}); ok {
+			return b.serializeProperties(complexType.Properties, object, depth+1)
+		}
+	}
+
+	return value
+}
+
+// serializeEnum returns the OData value of an enum: a member name, or for
+// flags a comma-separated list of member names
+func serializeEnum(enumType *models.EnumType, value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			if name, ok := serializeEnum(enumType, item).(string); ok {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ",")
+	case float64:
+		for _, member := range enumType.Members {
+			if float64(member.Value) == v {
+				return member.Name
+			}
+		}
+	}
+	return value
+}
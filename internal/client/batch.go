@@ -0,0 +1,382 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// batchPart groups operations that are sent as one part of a $batch request.
+// Reads are always sent on their own, writes are wrapped in a changeset
+// (v2) or atomicity group (v4).
+type batchPart struct {
+	indexes   []int
+	changeset bool
+}
+
+// ExecuteBatch sends the given operations to the service in a single $batch request.
+//
+// When atomic is true all write operations are placed in one changeset so the
+// service applies them all or none of them. Reads that follow the first write are
+// sent after that changeset. When atomic is false every write gets its own changeset.
+// Writes can refer to an earlier write in the same changeset with a "$<n>" path,
+// where n is the 1-based position of that operation in ops.
+func (c *ODataClient) ExecuteBatch(ctx context.Context, ops []*models.BatchOperation, atomic bool) ([]*models.BatchResult, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("batch contains no operations")
+	}
+
+	parts := c.groupBatchOperations(ops, atomic)
+
+	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
+	if err := c.fetchCSRFToken(ctx); err != nil {
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
+		}
+		// Continue without token - some services might not require it
+	}
+
+	var body []byte
+	var contentType string
+	var err error
+	if c.isV4.Load() {
+		body, err = c.buildJSONBatch(ops, parts)
+		contentType = constants.ContentTypeJSON
+	} else {
+		boundary := fmt.Sprintf("batch_%d", time.Now().UnixNano())
+		body, err = c.buildMultipartBatch(ops, parts, boundary)
+		contentType = fmt.Sprintf("%s; boundary=%s", constants.ContentTypeMultipartMixed, boundary)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Executing batch with %d operations in %d parts\n", len(ops), len(parts))
+	}
+
+	req, err := c.buildRequest(ctx, constants.POST, constants.BatchEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(constants.ContentType, contentType)
+	if !c.isV4.Load() {
+		req.Header.Set(constants.Accept, constants.ContentTypeMultipartMixed)
+	}
+	// Explicitly set content length to avoid any body length issues
+	req.ContentLength = int64(len(body))
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+
+	results := make([]*models.BatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = &models.BatchResult{
+			Index:  i,
+			Method: op.Method,
+			URL:    c.batchOperationURL(op),
+		}
+	}
+
+	// A dry run only records the batch request, there is no response to parse
+	if dryRunFromContext(ctx) != nil {
+		return results, nil
+	}
+
+	if c.isV4.Load() {
+		err = c.parseJSONBatchResponse(resp, results)
+	} else {
+		err = c.parseMultipartBatchResponse(resp, parts, results)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// groupBatchOperations splits operations into batch parts while preserving order
+func (c *ODataClient) groupBatchOperations(ops []*models.BatchOperation, atomic bool) []*batchPart {
+	var parts []*batchPart
+	var changeset *batchPart
+
+	for i, op := range ops {
+		if !isModifyingMethod(op.Method) {
+			parts = append(parts, &batchPart{indexes: []int{i}})
+			continue
+		}
+
+		if !atomic {
+			parts = append(parts, &batchPart{indexes: []int{i}, changeset: true})
+			continue
+		}
+
+		if changeset == nil {
+			changeset = &batchPart{changeset: true}
+			parts = append(parts, changeset)
+		}
+		changeset.indexes = append(changeset.indexes, i)
+	}
+
+	return parts
+}
+
+// batchReferenceID returns the id of the request that a path such as $1/Items refers to
+func batchReferenceID(path string) (string, bool) {
+	if !strings.HasPrefix(path, "$") {
+		return "", false
+	}
+	id := path[1:]
+	if i := strings.Index(id, "/"); i >= 0 {
+		id = id[:i]
+	}
+	if _, err := strconv.Atoi(id); err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+// batchOperationURL builds the service-relative URL for a batch operation
+func (c *ODataClient) batchOperationURL(op *models.BatchOperation) string {
+	target := op.Path
+	if len(op.Key) > 0 {
+		target = fmt.Sprintf("%s(%s)", target, c.buildKeyPredicate(op.Key))
+	}
+
+	if len(op.Query) > 0 {
+		params := url.Values{}
+		for k, v := range op.Query {
+			if v == "" {
+				continue
+			}
+			// Handle v2 to v4 query parameter translation
+			if c.isV4.Load() && k == constants.QueryInlineCount {
+				if v == "allpages" {
+					params.Set(constants.QueryCount, "true")
+				}
+				continue
+			}
+			params.Set(k, v)
+		}
+		if len(params) > 0 {
+			target += "?" + params.Encode()
+		}
+	}
+
+	return target
+}
+
+// buildMultipartBatch builds an OData v2 multipart/mixed batch body
+func (c *ODataClient) buildMultipartBatch(ops []*models.BatchOperation, parts []*batchPart, boundary string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for n, part := range parts {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+
+		if !part.changeset {
+			if err := c.writeHTTPPart(&buf, ops[part.indexes[0]], 0); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		changesetBoundary := fmt.Sprintf("changeset_%s_%d", strings.TrimPrefix(boundary, "batch_"), n)
+		fmt.Fprintf(&buf, "%s: %s; boundary=%s\r\n\r\n", constants.ContentType, constants.ContentTypeMultipartMixed, changesetBoundary)
+		for _, idx := range part.indexes {
+			fmt.Fprintf(&buf, "--%s\r\n", changesetBoundary)
+			if err := c.writeHTTPPart(&buf, ops[idx], idx+1); err != nil {
+				return nil, err
+			}
+		}
+		fmt.Fprintf(&buf, "--%s--\r\n", changesetBoundary)
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// writeHTTPPart writes a single application/http request part
+func (c *ODataClient) writeHTTPPart(buf *bytes.Buffer, op *models.BatchOperation, contentID int) error {
+	fmt.Fprintf(buf, "%s: %s\r\n", constants.ContentType, constants.ContentTypeHTTP)
+	fmt.Fprintf(buf, "%s: binary\r\n", constants.ContentTransferEncoding)
+	if contentID > 0 {
+		fmt.Fprintf(buf, "%s: %d\r\n", constants.ContentID, contentID)
+	}
+	buf.WriteString("\r\n")
+
+	fmt.Fprintf(buf, "%s %s HTTP/1.1\r\n", op.Method, c.batchOperationURL(op))
+	fmt.Fprintf(buf, "%s: %s\r\n", constants.Accept, constants.ContentTypeJSON)
+
+	if op.Body != nil && op.Method != constants.GET && op.Method != constants.DELETE {
+		jsonData, err := json.Marshal(op.Body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal batch operation body: %w", err)
+		}
+		fmt.Fprintf(buf, "%s: %s\r\n", constants.ContentType, constants.ContentTypeJSON)
+		fmt.Fprintf(buf, "Content-Length: %d\r\n\r\n", len(jsonData))
+		buf.Write(jsonData)
+		buf.WriteString("\r\n")
+		return nil
+	}
+
+	buf.WriteString("\r\n\r\n")
+	return nil
+}
+
+// buildJSONBatch builds an OData v4 JSON batch body
+func (c *ODataClient) buildJSONBatch(ops []*models.BatchOperation, parts []*batchPart) ([]byte, error) {
+	requests := make([]map[string]interface{}, 0, len(ops))
+
+	for n, part := range parts {
+		for _, idx := range part.indexes {
+			op := ops[idx]
+			request := map[string]interface{}{
+				"id":     strconv.Itoa(idx + 1),
+				"method": op.Method,
+				"url":    c.batchOperationURL(op),
+				"headers": map[string]string{
+					"accept": constants.ContentTypeJSON,
+				},
+			}
+			if part.changeset {
+				request["atomicityGroup"] = fmt.Sprintf("changeset%d", n+1)
+			}
+			// Requests that address an earlier request by its id must declare it
+			if id, ok := batchReferenceID(op.Path); ok {
+				request["dependsOn"] = []string{id}
+			}
+			if op.Body != nil && op.Method != constants.GET && op.Method != constants.DELETE {
+				request["headers"].(map[string]string)["content-type"] = constants.ContentTypeJSON
+				request["body"] = op.Body
+			}
+			requests = append(requests, request)
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"requests": requests})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+	}
+	return body, nil
+}
+
+// parseMultipartBatchResponse parses an OData v2 multipart/mixed batch response
+func (c *ODataClient) parseMultipartBatchResponse(resp *http.Response, parts []*batchPart, results []*models.BatchResult) error {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get(constants.ContentType))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return fmt.Errorf("unexpected batch response content type: %s", resp.Header.Get(constants.ContentType))
+	}
+
+	reader := multipart.NewReader(resp.Body, params["boundary"])
+	for _, part := range parts {
+		p, err := reader.NextPart()
+		if err != nil {
+			return fmt.Errorf("failed to read batch response part: %w", err)
+		}
+
+		partType, partParams, _ := mime.ParseMediaType(p.Header.Get(constants.ContentType))
+		if !strings.HasPrefix(partType, "multipart/") {
+			// A single response: either a read or a failed changeset
+			partResp, err := http.ReadResponse(bufio.NewReader(p), nil)
+			if err != nil {
+				return fmt.Errorf("failed to parse batch response part: %w", err)
+			}
+			for _, idx := range part.indexes {
+				c.fillBatchResult(results[idx], partResp)
+			}
+			continue
+		}
+
+		changesetReader := multipart.NewReader(p, partParams["boundary"])
+		for _, idx := range part.indexes {
+			cp, err := changesetReader.NextPart()
+			if err != nil {
+				return fmt.Errorf("failed to read changeset response part: %w", err)
+			}
+			partResp, err := http.ReadResponse(bufio.NewReader(cp), nil)
+			if err != nil {
+				return fmt.Errorf("failed to parse changeset response part: %w", err)
+			}
+			c.fillBatchResult(results[idx], partResp)
+		}
+	}
+
+	return nil
+}
+
+// parseJSONBatchResponse parses an OData v4 JSON batch response
+func (c *ODataClient) parseJSONBatchResponse(resp *http.Response, results []*models.BatchResult) error {
+	var batchResp struct {
+		Responses []struct {
+			ID     string          `json:"id"`
+			Status int             `json:"status"`
+			Body   json.RawMessage `json:"body"`
+		} `json:"responses"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return fmt.Errorf("failed to parse batch response: %w", err)
+	}
+
+	for _, r := range batchResp.Responses {
+		idx, err := strconv.Atoi(r.ID)
+		if err != nil || idx < 1 || idx > len(results) {
+			continue
+		}
+
+		body := []byte(r.Body)
+		if string(body) == "null" {
+			body = nil
+		}
+		c.fillBatchResult(results[idx-1], &http.Response{
+			StatusCode: r.Status,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		})
+	}
+
+	return nil
+}
+
+// fillBatchResult stores the status and parsed body of a batch sub-response
+func (c *ODataClient) fillBatchResult(result *models.BatchResult, resp *http.Response) {
+	defer resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	parsed, err := c.parseODataResponse(resp)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Value = parsed.Value
+}
+
+// isModifyingMethod reports whether the HTTP method changes data on the service
+func isModifyingMethod(method string) bool {
+	switch method {
+	case constants.POST, constants.PUT, constants.MERGE, constants.PATCH, constants.DELETE:
+		return true
+	}
+	return false
+}
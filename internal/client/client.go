@@ -9,12 +9,15 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/zmcp/odata-mcp/internal/constants"
 	"github.com/zmcp/odata-mcp/internal/metadata"
 	"github.com/zmcp/odata-mcp/internal/models"
+	"github.com/zmcp/odata-mcp/internal/transport"
 )
 
 // ODataClient handles HTTP communication with OData services
@@ -26,8 +29,11 @@ type ODataClient struct {
 	password       string
 	csrfToken      string
 	verbose        bool
-	sessionCookies []*http.Cookie // Track session cookies from server
-	isV4           bool           // Whether the service is OData v4
+	sessionCookies []*http.Cookie    // Track session cookies from server
+	isV4           atomic.Bool       // Whether the service is OData v4, set when fetching metadata
+	tokenSource    TokenSource       // OAuth2 or static bearer token provider
+	extraHeaders   map[string]string // Static headers added to every request
+	metadataCache  *MetadataCache    // On-disk $metadata cache, nil when disabled
 }
 
 // NewODataClient creates a new OData client
@@ -43,7 +49,6 @@ func NewODataClient(baseURL string, verbose bool) *ODataClient {
 			Timeout: time.Duration(constants.DefaultTimeout) * time.Second,
 		},
 		verbose: verbose,
-		isV4:    false, // Will be determined when fetching metadata
 	}
 }
 
@@ -53,11 +58,50 @@ func (c *ODataClient) SetBasicAuth(username, password string) {
 	c.password = password
 }
 
+// HTTPClient returns the HTTP client used for requests to the service
+func (c *ODataClient) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// SetBearerToken configures authentication with a fixed bearer token
+func (c *ODataClient) SetBearerToken(token string) {
+	c.tokenSource = NewStaticTokenSource(token)
+}
+
+// SetTokenSource configures bearer token authentication with tokens from ts
+func (c *ODataClient) SetTokenSource(ts TokenSource) {
+	c.tokenSource = ts
+}
+
 // SetCookies configures cookie authentication
 func (c *ODataClient) SetCookies(cookies map[string]string) {
 	c.cookies = cookies
 }
 
+// NewSession returns a client for a separate end-user session. It shares the
+// service configuration and HTTP transport, but keeps its own CSRF token and
+// session cookies so they never leak between sessions.
+func (c *ODataClient) NewSession() *ODataClient {
+	cookies := make(map[string]string, len(c.cookies))
+	for name, value := range c.cookies {
+		cookies[name] = value
+	}
+
+	session := &ODataClient{
+		baseURL:       c.baseURL,
+		httpClient:    c.httpClient,
+		cookies:       cookies,
+		username:      c.username,
+		password:      c.password,
+		verbose:       c.verbose,
+		tokenSource:   c.tokenSource,
+		extraHeaders:  c.extraHeaders,
+		metadataCache: c.metadataCache,
+	}
+	session.isV4.Store(c.isV4.Load())
+	return session
+}
+
 // buildRequest creates an HTTP request with proper headers and authentication
 func (c *ODataClient) buildRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
 	fullURL := c.baseURL + strings.TrimPrefix(endpoint, "/")
@@ -69,23 +113,50 @@ func (c *ODataClient) buildRequest(ctx context.Context, method, endpoint string,
 
 	// Set standard headers
 	req.Header.Set(constants.UserAgent, constants.DefaultUserAgent)
-	if c.isV4 {
+	if c.isV4.Load() {
 		req.Header.Set(constants.Accept, constants.ContentTypeODataJSONV4)
 	} else {
 		req.Header.Set(constants.Accept, constants.ContentTypeJSON)
 	}
 
-	// Set authentication
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
+	// Set configured static headers (connector routing, proxy authorization)
+	for name, value := range c.extraHeaders {
+		req.Header.Set(name, value)
 	}
 
-	// Set cookies
-	for name, value := range c.cookies {
-		req.AddCookie(&http.Cookie{
-			Name:  name,
-			Value: value,
-		})
+	// Pass-through end-user credentials replace the configured identity
+	if creds := transport.CredentialsFromContext(ctx); creds != nil {
+		if creds.Authorization != "" {
+			req.Header.Set("Authorization", creds.Authorization)
+		}
+		for name, value := range creds.Cookies {
+			req.AddCookie(&http.Cookie{
+				Name:  name,
+				Value: value,
+			})
+		}
+	} else {
+		// Set authentication
+		if c.tokenSource != nil && dryRunFromContext(ctx) != nil {
+			// Dry runs must not contact the token endpoint either
+			req.Header.Set(constants.Authorization, "Bearer "+redacted)
+		} else if c.tokenSource != nil {
+			token, err := c.tokenSource.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to obtain access token: %w", err)
+			}
+			req.Header.Set(constants.Authorization, "Bearer "+token)
+		} else if c.username != "" && c.password != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+
+		// Set cookies
+		for name, value := range c.cookies {
+			req.AddCookie(&http.Cookie{
+				Name:  name,
+				Value: value,
+			})
+		}
 	}
 	
 	// Add session cookies received from server
@@ -121,6 +192,11 @@ func (c *ODataClient) doRequest(req *http.Request) (*http.Response, error) {
 		}
 		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 	}
+
+	// In a dry run the request is recorded instead of sent
+	if dryRun := dryRunFromContext(req.Context()); dryRun != nil {
+		return dryRun.record(req, bodyBytes), nil
+	}
 	
 	return c.doRequestWithRetry(req, bodyBytes, false)
 }
@@ -142,6 +218,23 @@ func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, is
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 
+	// Handle an expired or revoked access token by fetching a new one once
+	if resp.StatusCode == http.StatusUnauthorized && c.tokenSource != nil && !isRetry &&
+		transport.CredentialsFromContext(req.Context()) == nil {
+		resp.Body.Close()
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Access token rejected, requesting a new one...\n")
+		}
+
+		c.tokenSource.Invalidate()
+		token, err := c.tokenSource.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to renew access token: %w", err)
+		}
+		req.Header.Set(constants.Authorization, "Bearer "+token)
+		return c.doRequestWithRetry(req, bodyBytes, true)
+	}
+
 	// Check if this is a modifying operation
 	modifyingMethods := []string{"POST", "PUT", "MERGE", "PATCH", "DELETE"}
 	isModifying := false
@@ -194,6 +287,11 @@ func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, is
 
 // fetchCSRFToken fetches a CSRF token from the service
 func (c *ODataClient) fetchCSRFToken(ctx context.Context) error {
+	// A dry run never contacts the service, keep the current token
+	if dryRunFromContext(ctx) != nil {
+		return nil
+	}
+
 	if c.verbose {
 		fmt.Fprintf(os.Stderr, "[VERBOSE] Fetching CSRF token...\n")
 	}
@@ -273,20 +371,118 @@ func min(a, b int) int {
 
 // GetMetadata fetches and parses the OData service metadata
 func (c *ODataClient) GetMetadata(ctx context.Context) (*models.ODataMetadata, error) {
+	body, err := c.fetchMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse metadata XML (to be implemented)
+	metadata, err := c.parseMetadataXML(body)
+	if err != nil {
+		// Fallback to service document if metadata parsing fails
+		return c.getServiceDocument(ctx)
+	}
+	c.resolveReferences(ctx, metadata)
+
+	return metadata, nil
+}
+
+// ParseMetadataDocument parses a $metadata document read from a local file.
+// Referenced documents are not fetched, the service is not contacted.
+func (c *ODataClient) ParseMetadataDocument(data []byte) (*models.ODataMetadata, error) {
+	metadata, err := c.parseMetadataXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata document: %w", err)
+	}
+	return metadata, nil
+}
+
+// GetRawMetadata returns the unparsed $metadata document, from the metadata
+// cache when it is fresh
+func (c *ODataClient) GetRawMetadata(ctx context.Context) ([]byte, error) {
+	return c.fetchMetadata(ctx)
+}
+
+// GetRawServiceDocument returns the unparsed JSON service document listing the
+// entity sets, singletons and function imports of the service
+func (c *ODataClient) GetRawServiceDocument(ctx context.Context) ([]byte, error) {
+	req, err := c.buildRequest(ctx, constants.GET, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(constants.Accept, constants.ContentTypeJSON)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service document: %w", err)
+	}
+	return body, nil
+}
+
+// fetchMetadata returns the $metadata document. With a metadata cache, a fresh
+// cached document is used as is and an older one is revalidated with its ETag
+// and Last-Modified date. When the service cannot be reached, the cached
+// document is used regardless of its age.
+func (c *ODataClient) fetchMetadata(ctx context.Context) ([]byte, error) {
+	var cached *cachedMetadata
+	if c.metadataCache != nil {
+		cached = c.metadataCache.load(c.baseURL)
+		if cached != nil && c.metadataCache.fresh(cached) {
+			if c.verbose {
+				fmt.Fprintf(os.Stderr, "[VERBOSE] Using cached metadata fetched at %s\n", cached.FetchedAt.Format(time.RFC3339))
+			}
+			return cached.Data, nil
+		}
+	}
+
 	req, err := c.buildRequest(ctx, constants.GET, constants.MetadataEndpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set(constants.Accept, constants.ContentTypeXML)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set(constants.IfNoneMatch, cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set(constants.IfModifiedSince, cached.LastModified)
+		}
+	}
 
 	resp, err := c.doRequest(req)
 	if err != nil {
+		if cached != nil {
+			return c.offlineMetadata(cached, err), nil
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Cached metadata is unchanged\n")
+		}
+		cached.FetchedAt = time.Now()
+		c.storeMetadata(cached)
+		return cached.Data, nil
+	}
 	if resp.StatusCode != http.StatusOK {
+		// Server errors are treated like an unreachable service, client errors such as 401 are reported
+		if cached != nil && resp.StatusCode >= http.StatusInternalServerError {
+			return c.offlineMetadata(cached, fmt.Errorf("HTTP %d", resp.StatusCode)), nil
+		}
 		return nil, c.parseError(resp)
 	}
 
@@ -294,32 +490,113 @@ func (c *ODataClient) GetMetadata(ctx context.Context) (*models.ODataMetadata, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to read metadata response: %w", err)
 	}
+	if c.metadataCache != nil {
+		c.storeMetadata(&cachedMetadata{
+			ServiceURL:   c.baseURL,
+			ETag:         resp.Header.Get(constants.ETag),
+			LastModified: resp.Header.Get(constants.LastModified),
+			FetchedAt:    time.Now(),
+			Data:         body,
+		})
+	}
+	return body, nil
+}
 
-	// Parse metadata XML (to be implemented)
-	metadata, err := c.parseMetadataXML(body)
+// offlineMetadata returns a cached document after a failed fetch
+func (c *ODataClient) offlineMetadata(cached *cachedMetadata, cause error) []byte {
+	fmt.Fprintf(os.Stderr, "WARNING: metadata could not be fetched (%v), using the copy cached at %s\n", cause, cached.FetchedAt.Format(time.RFC3339))
+	return cached.Data
+}
+
+// storeMetadata writes a document to the metadata cache. A cache that cannot
+// be written only costs the next start a download.
+func (c *ODataClient) storeMetadata(entry *cachedMetadata) {
+	if err := c.metadataCache.store(entry); err != nil && c.verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] %v\n", err)
+	}
+}
+
+// resolveReferences loads the entity types of entity sets that are defined in
+// documents included with edmx:Reference. Only documents on the service host
+// are fetched, so credentials are never sent to another server.
+func (c *ODataClient) resolveReferences(ctx context.Context, meta *models.ODataMetadata) {
+	unresolved := metadata.UnresolvedEntitySets(meta)
+	if len(unresolved) == 0 {
+		return
+	}
+
+	base, err := url.Parse(c.baseURL)
 	if err != nil {
-		// Fallback to service document if metadata parsing fails
-		return c.getServiceDocument(ctx)
+		return
+	}
+	for _, uri := range metadata.ReferencesFor(meta, unresolved) {
+		ref, err := base.Parse(uri)
+		if err != nil || ref.Host != base.Host {
+			if c.verbose {
+				fmt.Fprintf(os.Stderr, "[VERBOSE] Skipping referenced metadata %s: not on the service host\n", uri)
+			}
+			continue
+		}
+		if err := c.loadReference(ctx, meta, ref); err != nil && c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to load referenced metadata %s: %v\n", ref, err)
+		}
+	}
+}
+
+// loadReference fetches a referenced metadata document and merges its types
+func (c *ODataClient) loadReference(ctx context.Context, meta *models.ODataMetadata, ref *url.URL) error {
+	req, err := c.buildRequest(ctx, constants.GET, "", nil)
+	if err != nil {
+		return err
 	}
+	req.URL = ref
+	req.Host = ref.Host
+	req.Header.Set(constants.Accept, constants.ContentTypeXML)
 
-	return metadata, nil
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read referenced metadata: %w", err)
+	}
+	return metadata.MergeReferencedTypes(meta, body)
 }
 
 // GetEntitySet retrieves entities from an entity set
 func (c *ODataClient) GetEntitySet(ctx context.Context, entitySet string, options map[string]string) (*models.ODataResponse, error) {
-	endpoint := entitySet
-	
+	return c.getCollection(ctx, entitySet, options)
+}
+
+// GetNavigation retrieves the entities a navigation property of an entity
+// leads to, as a collection or a single entity
+func (c *ODataClient) GetNavigation(ctx context.Context, entitySet string, key map[string]interface{}, navProp string, options map[string]string, collection bool) (*models.ODataResponse, error) {
+	endpoint := fmt.Sprintf("%s(%s)/%s", entitySet, c.buildKeyPredicate(key), navProp)
+	if collection {
+		return c.getCollection(ctx, endpoint, options)
+	}
+	return c.getSingle(ctx, endpoint, options)
+}
+
+// getCollection retrieves the entity collection at endpoint
+func (c *ODataClient) getCollection(ctx context.Context, endpoint string, options map[string]string) (*models.ODataResponse, error) {
 	// Build query parameters with standard OData v2 parameters
 	params := url.Values{}
 	
 	// Always add JSON format for consistent responses (v2 only)
-	if !c.isV4 {
+	if !c.isV4.Load() {
 		params.Add(constants.QueryFormat, "json")
 	}
 	
 	// Add inline count for pagination support unless explicitly requesting count only
 	// OData v4 uses $count=true instead of $inlinecount
-	if !c.isV4 {
+	if !c.isV4.Load() {
 		if _, hasInlineCount := options[constants.QueryInlineCount]; !hasInlineCount {
 			params.Add(constants.QueryInlineCount, "allpages")
 		}
@@ -329,7 +606,7 @@ func (c *ODataClient) GetEntitySet(ctx context.Context, entitySet string, option
 	for key, value := range options {
 		if value != "" {
 			// Handle v2 to v4 query parameter translation
-			if c.isV4 && key == constants.QueryInlineCount {
+			if c.isV4.Load() && key == constants.QueryInlineCount {
 				// Translate $inlinecount to $count for v4
 				if value == "allpages" {
 					params.Set(constants.QueryCount, "true")
@@ -365,8 +642,16 @@ func (c *ODataClient) GetEntitySet(ctx context.Context, entitySet string, option
 func (c *ODataClient) GetEntity(ctx context.Context, entitySet string, key map[string]interface{}, options map[string]string) (*models.ODataResponse, error) {
 	// Build key predicate
 	keyPredicate := c.buildKeyPredicate(key)
-	endpoint := fmt.Sprintf("%s(%s)", entitySet, keyPredicate)
+	return c.getSingle(ctx, fmt.Sprintf("%s(%s)", entitySet, keyPredicate), options)
+}
 
+// GetSingleton retrieves an OData v4 singleton
+func (c *ODataClient) GetSingleton(ctx context.Context, singleton string, options map[string]string) (*models.ODataResponse, error) {
+	return c.getSingle(ctx, singleton, options)
+}
+
+// getSingle retrieves the single entity at endpoint
+func (c *ODataClient) getSingle(ctx context.Context, endpoint string, options map[string]string) (*models.ODataResponse, error) {
 	// Build query parameters
 	if len(options) > 0 {
 		params := url.Values{}
@@ -442,8 +727,24 @@ func (c *ODataClient) UpdateEntity(ctx context.Context, entitySet string, key ma
 	}
 
 	keyPredicate := c.buildKeyPredicate(key)
-	endpoint := fmt.Sprintf("%s(%s)", entitySet, keyPredicate)
+	return c.updateSingle(ctx, fmt.Sprintf("%s(%s)", entitySet, keyPredicate), data, method)
+}
 
+// UpdateSingleton updates an OData v4 singleton
+func (c *ODataClient) UpdateSingleton(ctx context.Context, singleton string, data map[string]interface{}, method string) (*models.ODataResponse, error) {
+	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
+	if err := c.fetchCSRFToken(ctx); err != nil {
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
+		}
+		// Continue without token - some services might not require it
+	}
+
+	return c.updateSingle(ctx, singleton, data, method)
+}
+
+// updateSingle sends the update of the single entity at endpoint
+func (c *ODataClient) updateSingle(ctx context.Context, endpoint string, data map[string]interface{}, method string) (*models.ODataResponse, error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal entity data: %w", err)
@@ -559,6 +860,158 @@ func (c *ODataClient) CallFunction(ctx context.Context, functionName string, par
 	return c.parseODataResponse(resp)
 }
 
+// CreateLink associates the target entity with an entity through a navigation
+// property, using $links (v2) or $ref (v4). Collection-valued navigation
+// properties get the target added, single-valued ones have it replaced.
+func (c *ODataClient) CreateLink(ctx context.Context, entitySet string, key map[string]interface{}, navProp, targetSet string, targetKey map[string]interface{}, collection bool) (*models.ODataResponse, error) {
+	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
+	if err := c.fetchCSRFToken(ctx); err != nil {
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
+		}
+		// Continue without token - some services might not require it
+	}
+
+	target := c.EntityURL(targetSet, targetKey)
+	var body map[string]string
+	if c.isV4.Load() {
+		body = map[string]string{"@odata.id": target}
+	} else {
+		body = map[string]string{"uri": target}
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal link: %w", err)
+	}
+
+	method := constants.PUT
+	if collection {
+		method = constants.POST
+	}
+	req, err := c.buildRequest(ctx, method, c.linkEndpoint(entitySet, key, navProp, nil), bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(constants.ContentType, constants.ContentTypeJSON)
+	req.ContentLength = int64(len(jsonData))
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return c.parseODataResponse(resp)
+}
+
+// DeleteLink removes the association of an entity with a target entity. The
+// target key is only needed for collection-valued navigation properties.
+func (c *ODataClient) DeleteLink(ctx context.Context, entitySet string, key map[string]interface{}, navProp string, targetKey map[string]interface{}) (*models.ODataResponse, error) {
+	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
+	if err := c.fetchCSRFToken(ctx); err != nil {
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
+		}
+		// Continue without token - some services might not require it
+	}
+
+	req, err := c.buildRequest(ctx, constants.DELETE, c.linkEndpoint(entitySet, key, navProp, targetKey), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return c.parseODataResponse(resp)
+}
+
+// LinkURL returns the absolute URL of the link between an entity and the
+// target of a navigation property
+func (c *ODataClient) LinkURL(entitySet string, key map[string]interface{}, navProp string, targetKey map[string]interface{}) string {
+	return c.baseURL + c.linkEndpoint(entitySet, key, navProp, targetKey)
+}
+
+// linkEndpoint returns EntitySet(key)/$links/Nav(targetKey) for v2 and
+// EntitySet(key)/Nav(targetKey)/$ref for v4
+func (c *ODataClient) linkEndpoint(entitySet string, key map[string]interface{}, navProp string, targetKey map[string]interface{}) string {
+	target := navProp
+	if len(targetKey) > 0 {
+		target = fmt.Sprintf("%s(%s)", navProp, c.buildKeyPredicate(targetKey))
+	}
+	if c.isV4.Load() {
+		return fmt.Sprintf("%s(%s)/%s/$ref", entitySet, c.buildKeyPredicate(key), target)
+	}
+	return fmt.Sprintf("%s(%s)/$links/%s", entitySet, c.buildKeyPredicate(key), target)
+}
+
+// CallBoundOperation calls an OData v4 bound function (GET) or action (POST).
+// A nil key binds the operation to the entity set or singleton itself.
+func (c *ODataClient) CallBoundOperation(ctx context.Context, entitySet string, key map[string]interface{}, qualifiedName string, parameters map[string]interface{}, method string) (*models.ODataResponse, error) {
+	endpoint := c.boundEndpoint(entitySet, key, qualifiedName)
+
+	var req *http.Request
+	var err error
+
+	if method == constants.GET {
+		// Bound functions take their parameters inline: NS.Func(p1=...,p2=...)
+		names := make([]string, 0, len(parameters))
+		for name := range parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		paramStrings := make([]string, 0, len(names))
+		for _, name := range names {
+			paramStrings = append(paramStrings, fmt.Sprintf("%s=%s", name, c.formatInlineParameter(parameters[name])))
+		}
+		endpoint += "(" + strings.Join(paramStrings, ",") + ")"
+		req, err = c.buildRequest(ctx, constants.GET, endpoint, nil)
+	} else {
+		// Always fetch a fresh CSRF token for modifying operations (Python behavior)
+		if err := c.fetchCSRFToken(ctx); err != nil {
+			if c.verbose {
+				fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
+			}
+		}
+
+		// Bound actions take their parameters in the body
+		jsonData, marshalErr := json.Marshal(parameters)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal action parameters: %w", marshalErr)
+		}
+
+		req, err = c.buildRequest(ctx, constants.POST, endpoint, bytes.NewReader(jsonData))
+		if err == nil {
+			req.Header.Set(constants.ContentType, constants.ContentTypeJSON)
+			req.ContentLength = int64(len(jsonData))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return c.parseODataResponse(resp)
+}
+
+// boundEndpoint returns the path of a bound operation: EntitySet(key)/NS.Name,
+// or EntitySet/NS.Name without a key
+func (c *ODataClient) boundEndpoint(entitySet string, key map[string]interface{}, qualifiedName string) string {
+	if len(key) > 0 {
+		return fmt.Sprintf("%s(%s)/%s", entitySet, c.buildKeyPredicate(key), qualifiedName)
+	}
+	return fmt.Sprintf("%s/%s", entitySet, qualifiedName)
+}
+
 // buildKeyPredicate builds OData key predicate from key-value pairs
 func (c *ODataClient) buildKeyPredicate(key map[string]interface{}) string {
 	if len(key) == 1 {
@@ -576,6 +1029,26 @@ func (c *ODataClient) buildKeyPredicate(key map[string]interface{}) string {
 	return strings.Join(parts, ",")
 }
 
+// EntityURL returns the absolute URL of a single entity
+func (c *ODataClient) EntityURL(entitySet string, key map[string]interface{}) string {
+	return fmt.Sprintf("%s%s(%s)", c.baseURL, entitySet, c.buildKeyPredicate(key))
+}
+
+// SingletonURL returns the absolute URL of a singleton
+func (c *ODataClient) SingletonURL(singleton string) string {
+	return c.baseURL + singleton
+}
+
+// BoundOperationURL returns the absolute URL of a bound function or action
+func (c *ODataClient) BoundOperationURL(entitySet string, key map[string]interface{}, qualifiedName string) string {
+	return c.baseURL + c.boundEndpoint(entitySet, key, qualifiedName)
+}
+
+// FunctionURL returns the absolute URL of a function import
+func (c *ODataClient) FunctionURL(functionName string) string {
+	return c.baseURL + functionName
+}
+
 // formatKeyValue formats a key value for OData URL
 func (c *ODataClient) formatKeyValue(value interface{}) string {
 	switch v := value.(type) {
@@ -594,6 +1067,20 @@ func (c *ODataClient) formatKeyValue(value interface{}) string {
 	}
 }
 
+// formatInlineParameter formats a parameter written inside the URL path, as
+// bound functions take them. Quotes in strings are doubled and the value is
+// path-escaped so characters such as ? # / cannot end the path segment.
+func (c *ODataClient) formatInlineParameter(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + url.PathEscape(strings.ReplaceAll(v, "'", "''")) + "'"
+	case int, int32, int64, float32, float64, bool:
+		return c.formatKeyValue(v)
+	default:
+		return c.formatInlineParameter(fmt.Sprintf("%v", v))
+	}
+}
+
 // formatFunctionParameter formats a function parameter for OData URL
 func (c *ODataClient) formatFunctionParameter(key string, value interface{}) string {
 	switch v := value.(type) {
@@ -635,7 +1122,7 @@ func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResp
 	}
 
 	// Parse using the appropriate parser
-	parsedResponse, err := parseODataResponse(body, c.isV4)
+	parsedResponse, err := parseODataResponse(body, c.isV4.Load())
 	if err != nil {
 		return nil, err
 	}
@@ -646,7 +1133,7 @@ func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResp
 	switch v := parsedResponse.(type) {
 	case map[string]interface{}:
 		// Check for v4 format
-		if c.isV4 {
+		if c.isV4.Load() {
 			// OData v4 format
 			if value, ok := v["value"]; ok {
 				odataResp.Value = value
@@ -805,7 +1292,7 @@ func (c *ODataClient) parseMetadataXML(data []byte) (*models.ODataMetadata, erro
 	}
 	
 	// Set the client's v4 flag based on metadata version
-	c.isV4 = meta.Version == "4.0" || meta.Version == "4.01"
+	c.isV4.Store(meta.Version == "4.0" || meta.Version == "4.01")
 	
 	return meta, nil
 }
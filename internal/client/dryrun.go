@@ -0,0 +1,106 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces credential values in dry-run output
+const redacted = "[REDACTED]"
+
+// sensitiveHeaderParts mark header names whose values are credentials
+var sensitiveHeaderParts = []string{"authorization", "cookie", "token", "secret", "password", "api-key", "apikey", "session"}
+
+// DryRunRequest is an HTTP request the client built but did not send
+type DryRunRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+// DryRun records the requests of a client call instead of sending them
+type DryRun struct {
+	mu       sync.Mutex
+	requests []*DryRunRequest
+}
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context in which client calls build their requests but
+// never contact the service. The built requests are recorded in the returned DryRun.
+func WithDryRun(ctx context.Context) (context.Context, *DryRun) {
+	d := &DryRun{}
+	return context.WithValue(ctx, dryRunKey{}, d), d
+}
+
+func dryRunFromContext(ctx context.Context) *DryRun {
+	d, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return d
+}
+
+// Requests returns the recorded requests in the order they were built
+func (d *DryRun) Requests() []*DryRunRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*DryRunRequest(nil), d.requests...)
+}
+
+// record stores req with credentials redacted and answers it with an empty response
+func (d *DryRun) record(req *http.Request, body []byte) *http.Response {
+	headers := make(map[string]string, len(req.Header))
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		headers[name] = redactHeader(name, strings.Join(req.Header.Values(name), ", "))
+	}
+
+	recorded := &DryRunRequest{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: headers,
+	}
+	if len(body) > 0 {
+		var decoded interface{}
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			recorded.Body = decoded
+		} else {
+			recorded.Body = string(body)
+		}
+	}
+
+	d.mu.Lock()
+	d.requests = append(d.requests, recorded)
+	d.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}
+}
+
+// redactHeader hides credential values, keeping the authorization scheme
+func redactHeader(name, value string) string {
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveHeaderParts {
+		if !strings.Contains(lower, part) {
+			continue
+		}
+		if scheme, _, found := strings.Cut(value, " "); found && strings.Contains(lower, "authorization") {
+			return scheme + " " + redacted
+		}
+		return redacted
+	}
+	return value
+}
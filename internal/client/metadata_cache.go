@@ -0,0 +1,106 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MetadataCache stores $metadata documents on disk, one per service URL, with
+// the validators needed to revalidate them conditionally
+type MetadataCache struct {
+	dir string
+	ttl time.Duration // Age within which a cached document is used without revalidation
+}
+
+// cachedMetadata is a cached $metadata document and its validators
+type cachedMetadata struct {
+	ServiceURL   string    `json:"service_url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	Data         []byte    `json:"-"`
+}
+
+// NewMetadataCache creates a metadata cache in dir. With a zero ttl every use
+// of a cached document is revalidated with the service.
+func NewMetadataCache(dir string, ttl time.Duration) (*MetadataCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create metadata cache directory: %w", err)
+	}
+	return &MetadataCache{dir: dir, ttl: ttl}, nil
+}
+
+// SetMetadataCache enables the on-disk metadata cache for GetMetadata
+func (c *ODataClient) SetMetadataCache(cache *MetadataCache) {
+	c.metadataCache = cache
+}
+
+// paths returns the document and validator files of a service URL
+func (m *MetadataCache) paths(serviceURL string) (string, string) {
+	sum := sha256.Sum256([]byte(serviceURL))
+	name := hex.EncodeToString(sum[:16])
+	return filepath.Join(m.dir, name+".xml"), filepath.Join(m.dir, name+".json")
+}
+
+// load returns the cached document of a service URL, nil when there is none
+func (m *MetadataCache) load(serviceURL string) *cachedMetadata {
+	dataPath, infoPath := m.paths(serviceURL)
+	info, err := os.ReadFile(infoPath)
+	if err != nil {
+		return nil
+	}
+	var entry cachedMetadata
+	if err := json.Unmarshal(info, &entry); err != nil || entry.ServiceURL != serviceURL {
+		return nil
+	}
+	if entry.Data, err = os.ReadFile(dataPath); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes a document and its validators. The document is written first,
+// so validators never describe a document that is not on disk.
+func (m *MetadataCache) store(entry *cachedMetadata) error {
+	dataPath, infoPath := m.paths(entry.ServiceURL)
+	info, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata cache entry: %w", err)
+	}
+	if err := writeFileAtomic(dataPath, entry.Data); err != nil {
+		return fmt.Errorf("failed to write cached metadata: %w", err)
+	}
+	if err := writeFileAtomic(infoPath, info); err != nil {
+		return fmt.Errorf("failed to write cached metadata: %w", err)
+	}
+	return nil
+}
+
+// fresh reports whether a cached document may be used without revalidation
+func (m *MetadataCache) fresh(entry *cachedMetadata) bool {
+	return m.ttl > 0 && time.Since(entry.FetchedAt) < m.ttl
+}
+
+// writeFileAtomic replaces a file by renaming a temporary file over it, so
+// concurrent readers see either the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+)
+
+// TokenSource supplies bearer tokens for requests to the OData service
+type TokenSource interface {
+	// Token returns a valid access token, acquiring a new one if needed
+	Token(ctx context.Context) (string, error)
+
+	// Invalidate discards the current token after the service rejected it
+	Invalidate()
+}
+
+// OAuth2Config describes how to obtain access tokens from an OAuth2 token endpoint.
+// The refresh token grant is used when RefreshToken is set, client credentials otherwise.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RefreshToken string
+	HTTPClient   *http.Client // Client for the token endpoint, defaults to one with the default timeout
+}
+
+// staticTokenSource always returns the same pre-issued token
+type staticTokenSource struct {
+	token string
+}
+
+// NewStaticTokenSource creates a token source for a fixed bearer token
+func NewStaticTokenSource(token string) TokenSource {
+	return &staticTokenSource{token: token}
+}
+
+func (s *staticTokenSource) Token(ctx context.Context) (string, error) {
+	return s.token, nil
+}
+
+func (s *staticTokenSource) Invalidate() {}
+
+// oauth2TokenSource acquires tokens from a token endpoint and caches them until shortly before expiry
+type oauth2TokenSource struct {
+	config       OAuth2Config
+	httpClient   *http.Client
+	verbose      bool
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiry       time.Time
+}
+
+// tokenResponse is the token endpoint response defined by RFC 6749 section 5.1
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	RefreshToken     string      `json:"refresh_token"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// NewOAuth2TokenSource creates a token source for the client credentials or refresh token grant
+func NewOAuth2TokenSource(config OAuth2Config, verbose bool) TokenSource {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: time.Duration(constants.DefaultTimeout) * time.Second,
+		}
+	}
+
+	return &oauth2TokenSource{
+		config:       config,
+		httpClient:   httpClient,
+		verbose:      verbose,
+		refreshToken: config.RefreshToken,
+	}
+}
+
+func (s *oauth2TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Renew ahead of expiry so a token does not lapse while a request is in flight
+	if s.accessToken != "" && (s.expiry.IsZero() || time.Until(s.expiry) > constants.DefaultTokenExpiryMargin) {
+		return s.accessToken, nil
+	}
+
+	if err := s.fetchToken(ctx); err != nil {
+		return "", err
+	}
+	return s.accessToken, nil
+}
+
+func (s *oauth2TokenSource) Invalidate() {
+	s.mu.Lock()
+	s.accessToken = ""
+	s.mu.Unlock()
+}
+
+// fetchToken requests a new access token. The caller must hold s.mu.
+func (s *oauth2TokenSource) fetchToken(ctx context.Context) error {
+	form := url.Values{}
+	if s.refreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", s.refreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	form.Set("client_id", s.config.ClientID)
+	if s.config.ClientSecret != "" {
+		form.Set("client_secret", s.config.ClientSecret)
+	}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] Requesting OAuth2 token (%s) from %s\n", form.Get("grant_type"), s.config.TokenURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, constants.POST, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set(constants.ContentType, "application/x-www-form-urlencoded")
+	req.Header.Set(constants.Accept, constants.ContentTypeJSON)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return fmt.Errorf("failed to parse token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		if token.Error != "" {
+			return fmt.Errorf("%s: token endpoint returned %s: %s", constants.ErrAuthenticationFailed, token.Error, token.ErrorDescription)
+		}
+		return fmt.Errorf("%s: token endpoint returned status %d", constants.ErrAuthenticationFailed, resp.StatusCode)
+	}
+
+	s.accessToken = token.AccessToken
+	s.expiry = time.Time{}
+	if seconds, err := token.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		s.expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	// Token endpoints may rotate refresh tokens
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "[VERBOSE] OAuth2 token acquired, expires in %s\n", token.ExpiresIn)
+	}
+
+	return nil
+}
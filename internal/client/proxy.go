@@ -0,0 +1,143 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// SetProxy routes requests through proxyURL, except for hosts matching noProxy.
+// noProxy uses the NO_PROXY syntax: comma-separated host names, domain
+// suffixes (.corp.local), IP addresses, CIDR ranges, optional :port, or *.
+// An empty noProxy falls back to the NO_PROXY environment variable.
+// Without SetProxy the HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables apply.
+func (c *ODataClient) SetProxy(proxyURL, noProxy string) error {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid proxy URL: %s", proxyURL)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", parsed.Scheme)
+	}
+
+	if noProxy == "" {
+		noProxy = os.Getenv("NO_PROXY")
+		if noProxy == "" {
+			noProxy = os.Getenv("no_proxy")
+		}
+	}
+	bypass := parseNoProxy(noProxy)
+
+	c.transport().Proxy = func(req *http.Request) (*url.URL, error) {
+		if bypass.matches(req.URL) {
+			return nil, nil
+		}
+		return parsed, nil
+	}
+	return nil
+}
+
+// SetExtraHeaders configures static headers sent with every request, such as
+// SAP-Connectivity-SCC-Location_ID. Proxy-* headers are also sent on CONNECT
+// requests so they reach the proxy for HTTPS services.
+func (c *ODataClient) SetExtraHeaders(headers map[string]string) {
+	c.extraHeaders = headers
+
+	connectHeader := http.Header{}
+	for name, value := range headers {
+		if strings.HasPrefix(strings.ToLower(name), "proxy-") {
+			connectHeader.Set(name, value)
+		}
+	}
+	if len(connectHeader) > 0 {
+		c.transport().ProxyConnectHeader = connectHeader
+	}
+}
+
+// noProxyRule is one entry of a NO_PROXY list
+type noProxyRule struct {
+	host   string // lower-case host or domain suffix (leading dot)
+	port   string
+	ipNet  *net.IPNet
+	ip     net.IP
+	suffix bool
+}
+
+// noProxyList decides which hosts bypass the proxy
+type noProxyList struct {
+	all   bool
+	rules []noProxyRule
+}
+
+func parseNoProxy(value string) noProxyList {
+	var list noProxyList
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			list.all = true
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			list.rules = append(list.rules, noProxyRule{ipNet: ipNet})
+			continue
+		}
+
+		rule := noProxyRule{host: entry}
+		if host, port, err := net.SplitHostPort(entry); err == nil {
+			rule.host, rule.port = host, port
+		}
+		if ip := net.ParseIP(strings.Trim(rule.host, "[]")); ip != nil {
+			rule.ip = ip
+		} else if strings.HasPrefix(rule.host, "*.") || strings.HasPrefix(rule.host, ".") {
+			rule.host = "." + strings.TrimLeft(rule.host, "*.")
+			rule.suffix = true
+		}
+		list.rules = append(list.rules, rule)
+	}
+	return list
+}
+
+// matches reports whether requests to u should bypass the proxy
+func (l noProxyList) matches(u *url.URL) bool {
+	if l.all {
+		return true
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	ip := net.ParseIP(host)
+
+	for _, rule := range l.rules {
+		if rule.port != "" && rule.port != port {
+			continue
+		}
+		switch {
+		case rule.ipNet != nil:
+			if ip != nil && rule.ipNet.Contains(ip) {
+				return true
+			}
+		case rule.ip != nil:
+			if ip != nil && rule.ip.Equal(ip) {
+				return true
+			}
+		case rule.suffix:
+			if strings.HasSuffix(host, rule.host) || host == rule.host[1:] {
+				return true
+			}
+		default:
+			// Plain domains also match their subdomains, like curl and Go's httpproxy
+			if host == rule.host || strings.HasSuffix(host, "."+rule.host) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// TLSOptions describes the TLS settings for connections to the OData service
+type TLSOptions struct {
+	CAFile             string // PEM bundle of additional trusted CAs
+	CertFile           string // PEM or PKCS#12 client certificate, may also contain the key
+	KeyFile            string // PEM private key of the client certificate
+	CertPassword       string // Password of a PKCS#12 client certificate
+	MinVersion         string // Minimum TLS version: 1.0, 1.1, 1.2 or 1.3
+	InsecureSkipVerify bool   // Skip server certificate verification (development only)
+}
+
+// tlsVersions maps the accepted --tls-min-version values to crypto/tls constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// BuildTLSConfig creates a TLS configuration from opts
+func BuildTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.MinVersion != "" {
+		version, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(opts.MinVersion), "tls")]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q (use 1.0, 1.1, 1.2 or 1.3)", opts.MinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	// Trust the system roots plus the CA bundle
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle: %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.CertFile != "" {
+		cert, err := loadClientCertificate(opts.CertFile, opts.KeyFile, opts.CertPassword)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if opts.KeyFile != "" {
+		return nil, fmt.Errorf("client key given without a client certificate")
+	}
+
+	return tlsConfig, nil
+}
+
+// loadClientCertificate loads a PEM certificate and key. Without keyFile the
+// key is expected in certFile. Files named .p12 or .pfx are read as PKCS#12.
+func loadClientCertificate(certFile, keyFile, password string) (tls.Certificate, error) {
+	switch strings.ToLower(filepath.Ext(certFile)) {
+	case ".p12", ".pfx":
+		if keyFile != "" {
+			return tls.Certificate{}, fmt.Errorf("client key given with a PKCS#12 client certificate, which contains the key")
+		}
+		return loadPKCS12Certificate(certFile, password)
+	}
+
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+	keyPEM := certPEM
+	if keyFile != "" {
+		keyPEM, err = os.ReadFile(keyFile)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to read client key: %w", err)
+		}
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	// Keep the parsed leaf for reporting subject and expiry
+	if cert.Leaf == nil {
+		cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+	}
+
+	return cert, nil
+}
+
+// loadPKCS12Certificate loads the key, certificate and intermediate CAs of a PKCS#12 file
+func loadPKCS12Certificate(certFile, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load PKCS#12 client certificate: %w", err)
+	}
+
+	cert := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for _, ca := range chain {
+		cert.Certificate = append(cert.Certificate, ca.Raw)
+	}
+	return cert, nil
+}
+
+// TLSVersionName returns the display name of a crypto/tls version constant
+func TLSVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return "TLS " + name
+		}
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// SetTLSConfig configures the TLS settings used for all requests to the service
+func (c *ODataClient) SetTLSConfig(tlsConfig *tls.Config) {
+	c.transport().TLSClientConfig = tlsConfig
+}
+
+// transport returns the client's own HTTP transport, cloning the default
+// transport on first use so settings never leak into http.DefaultTransport
+func (c *ODataClient) transport() *http.Transport {
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	c.httpClient.Transport = t
+	return t
+}
@@ -1,9 +1,14 @@
 package config
 
+import "time"
+
 // Config holds all configuration options for the OData MCP bridge
 type Config struct {
 	// Service configuration
-	ServiceURL string `mapstructure:"service_url"`
+	ServiceURL   string `mapstructure:"service_url"`
+	Name         string `mapstructure:"name"`          // Service name in multi-service mode
+	ServicesFile string `mapstructure:"services_file"` // File listing several services to serve together
+	ConfigFile   string `mapstructure:"config_file"`   // YAML, JSON or TOML file with these options
 
 	// Authentication
 	Username     string            `mapstructure:"username"`
@@ -12,6 +17,33 @@ type Config struct {
 	CookieString string            `mapstructure:"cookie_string"`
 	Cookies      map[string]string // Parsed cookies
 
+	// Bearer token and OAuth2 authentication
+	BearerToken        string `mapstructure:"bearer_token"`
+	OAuth2TokenURL     string `mapstructure:"oauth2_token_url"`
+	OAuth2ClientID     string `mapstructure:"oauth2_client_id"`
+	OAuth2ClientSecret string `mapstructure:"oauth2_client_secret"`
+	OAuth2Scopes       string `mapstructure:"oauth2_scopes"`
+	OAuth2RefreshToken string `mapstructure:"oauth2_refresh_token"`
+
+	// TLS options
+	CAFile             string `mapstructure:"ca_file"`
+	ClientCert         string `mapstructure:"client_cert"`
+	ClientKey          string `mapstructure:"client_key"`
+	ClientCertPassword string `mapstructure:"client_cert_password"`
+	TLSMinVersion      string `mapstructure:"tls_min_version"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+
+	// Proxy and static request headers
+	Proxy        string            `mapstructure:"proxy"`
+	NoProxy      string            `mapstructure:"no_proxy"`
+	Headers      []string          `mapstructure:"headers"`
+	ExtraHeaders map[string]string // Parsed from Headers
+
+	// Pass-through end-user authentication (HTTP transports only)
+	PassThroughAuth        bool     `mapstructure:"pass_through_auth"`
+	PassThroughCookies     string   `mapstructure:"pass_through_cookies"`
+	PassThroughCookieNames []string // Parsed from PassThroughCookies
+
 	// Tool naming options
 	ToolPrefix  string `mapstructure:"tool_prefix"`
 	ToolPostfix string `mapstructure:"tool_postfix"`
@@ -24,6 +56,32 @@ type Config struct {
 	AllowedEntities  []string // Parsed from Entities
 	AllowedFunctions []string // Parsed from Functions
 
+	// Write protection and per-entity set operation rules
+	ReadOnly         bool            `mapstructure:"read_only"`         // Suppress all create, update, delete and POST function tools
+	NoPostFunctions  bool            `mapstructure:"no_post_functions"` // Suppress function imports invoked with POST
+	EntityOperations []string        `mapstructure:"entity_operations"` // Rules like "Orders:get,filter" or "Products:!delete"
+	OperationRules   []OperationRule // Parsed from EntityOperations
+
+	// Two-phase confirmation of delete, update and POST function calls
+	ConfirmWrites bool          `mapstructure:"confirm_writes"`
+	ConfirmTTL    time.Duration `mapstructure:"confirm_ttl"` // Token lifetime, defaults to constants.DefaultConfirmationTTL
+
+	// Show the HTTP request of write and function tools instead of sending it
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Check tool call arguments against the generated input schemas before calling the service
+	ValidateArgs bool `mapstructure:"validate_args"`
+
+	// Metadata cache and local metadata
+	MetadataCacheDir string        `mapstructure:"metadata_cache_dir"` // Directory caching $metadata per service URL
+	MetadataCacheTTL time.Duration `mapstructure:"metadata_cache_ttl"` // Age within which cached metadata is used without revalidation
+	MetadataFile     string        `mapstructure:"metadata_file"`      // Local EDMX file used instead of fetching $metadata
+	MetadataRefresh  time.Duration `mapstructure:"metadata_refresh"`   // Interval of periodic metadata reloads (0 disables)
+	ReloadTool       bool          `mapstructure:"reload_tool"`        // Add a tool that reloads the metadata on demand
+
+	// Per-entity set overrides, keyed by entity set name (configuration file only)
+	EntityOverrides map[string]EntityOverride `mapstructure:"entity_overrides"`
+
 	// Output and debugging
 	Verbose   bool `mapstructure:"verbose"`
 	Debug     bool `mapstructure:"debug"`
@@ -42,6 +100,14 @@ type Config struct {
 	MaxItems        int `mapstructure:"max_items"`         // Maximum number of items in response
 }
 
+// EntityOverride customizes the tools generated for one entity set
+type EntityOverride struct {
+	ReadOnly          bool              `mapstructure:"read_only"`          // Suppress create, update and delete tools
+	HiddenFields      []string          `mapstructure:"hidden_fields"`      // Properties removed from tool schemas and responses
+	Description       string            `mapstructure:"description"`        // Appended to the description of every tool of the entity set
+	FieldDescriptions map[string]string `mapstructure:"field_descriptions"` // Property descriptions for create/update tools
+}
+
 // HasBasicAuth returns true if username and password are configured
 func (c *Config) HasBasicAuth() bool {
 	return c.Username != "" && c.Password != ""
@@ -52,6 +118,21 @@ func (c *Config) HasCookieAuth() bool {
 	return len(c.Cookies) > 0
 }
 
+// HasBearerToken returns true if a static bearer token is configured
+func (c *Config) HasBearerToken() bool {
+	return c.BearerToken != ""
+}
+
+// HasOAuth2 returns true if an OAuth2 token endpoint is configured
+func (c *Config) HasOAuth2() bool {
+	return c.OAuth2TokenURL != ""
+}
+
+// HasTLSConfig returns true if any TLS option differs from the defaults
+func (c *Config) HasTLSConfig() bool {
+	return c.CAFile != "" || c.ClientCert != "" || c.ClientKey != "" || c.TLSMinVersion != "" || c.InsecureSkipVerify
+}
+
 // UsePostfix returns true if tool postfix should be used instead of prefix
 func (c *Config) UsePostfix() bool {
 	return !c.NoPostfix
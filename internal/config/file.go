@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadFile reads options from a YAML, JSON or TOML file, selected by the file
+// extension. Keys are the mapstructure tags of Config. Only options present in
+// the file are changed, so flags and environment variables can be applied on top.
+func (c *Config) LoadFile(path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := v.Unmarshal(c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// EntityOverride returns the override for an entity set. Entity set names are
+// matched case-insensitively because configuration keys are not case-preserving.
+func (c *Config) EntityOverride(entitySet string) (EntityOverride, bool) {
+	if override, ok := c.EntityOverrides[entitySet]; ok {
+		return override, true
+	}
+	for name, override := range c.EntityOverrides {
+		if strings.EqualFold(name, entitySet) {
+			return override, true
+		}
+	}
+	return EntityOverride{}, false
+}
+
+// IsHidden reports whether a property is listed in HiddenFields
+func (o EntityOverride) IsHidden(property string) bool {
+	for _, field := range o.HiddenFields {
+		if strings.EqualFold(field, property) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldDescription returns the custom description of a property, if any
+func (o EntityOverride) FieldDescription(property string) (string, bool) {
+	for name, description := range o.FieldDescriptions {
+		if strings.EqualFold(name, property) {
+			return description, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+)
+
+// entityOperations are the operations an operation rule can name
+var entityOperations = []string{
+	constants.OpFilter,
+	constants.OpCount,
+	constants.OpSearch,
+	constants.OpGet,
+	constants.OpCreate,
+	constants.OpUpdate,
+	constants.OpDelete,
+	constants.OpLink,
+	constants.OpUnlink,
+}
+
+// OperationRule restricts the tools generated for matching entity sets.
+// When Allow is set only the listed operations are exposed; Deny removes operations.
+type OperationRule struct {
+	Entity string   // Entity set name, supports * wildcards
+	Allow  []string // Allowed operations, empty allows all
+	Deny   []string // Denied operations
+}
+
+// ParseOperationRules parses rules such as "Orders:get,filter" (allowlist) and
+// "Products:!delete" (denylist). Both forms may be combined in one rule.
+func ParseOperationRules(defs []string) ([]OperationRule, error) {
+	rules := make([]OperationRule, 0, len(defs))
+	for _, def := range defs {
+		entity, ops, found := strings.Cut(def, ":")
+		entity = strings.TrimSpace(entity)
+		if !found || entity == "" {
+			return nil, fmt.Errorf("invalid entity operation rule %q, expected EntitySet:op1,op2 or EntitySet:!op", def)
+		}
+
+		rule := OperationRule{Entity: entity}
+		for _, op := range strings.Split(ops, ",") {
+			op = strings.ToLower(strings.TrimSpace(op))
+			if op == "" {
+				continue
+			}
+			deny := strings.HasPrefix(op, "!")
+			op = strings.TrimPrefix(op, "!")
+			if !isEntityOperation(op) {
+				return nil, fmt.Errorf("unknown operation %q in rule %q (use %s)", op, def, strings.Join(entityOperations, ", "))
+			}
+			if deny {
+				rule.Deny = append(rule.Deny, op)
+			} else {
+				rule.Allow = append(rule.Allow, op)
+			}
+		}
+		if len(rule.Allow) == 0 && len(rule.Deny) == 0 {
+			return nil, fmt.Errorf("entity operation rule %q lists no operations", def)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Permits reports whether the rule allows an operation
+func (r OperationRule) Permits(operation string) bool {
+	for _, op := range r.Deny {
+		if op == operation {
+			return false
+		}
+	}
+	if len(r.Allow) == 0 {
+		return true
+	}
+	for _, op := range r.Allow {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func isEntityOperation(op string) bool {
+	for _, known := range entityOperations {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // OData XML namespaces
@@ -80,6 +81,10 @@ const (
 	UserAgent       = "User-Agent"
 	IfMatch         = "If-Match"
 	IfNoneMatch     = "If-None-Match"
+	IfModifiedSince = "If-Modified-Since"
+	ETag            = "ETag"
+	LastModified    = "Last-Modified"
+	ContentID       = "Content-ID"
 )
 
 // Content types
@@ -92,6 +97,13 @@ const (
 	ContentTypeODataAtom  = "application/atom+xml;type=entry"
 )
 
+// $batch part headers and content types
+const (
+	ContentTransferEncoding   = "Content-Transfer-Encoding"
+	ContentTypeMultipartMixed = "multipart/mixed"
+	ContentTypeHTTP           = "application/http"
+)
+
 // OData metadata endpoints
 const (
 	MetadataEndpoint     = "$metadata"
@@ -108,7 +120,10 @@ const (
 	OpCreate = "create"
 	OpUpdate = "update"
 	OpDelete = "delete"
+	OpLink   = "link"
+	OpUnlink = "unlink"
 	OpInfo   = "info"
+	OpBatch  = "batch"
 )
 
 // Tool operation names (for shrinking)
@@ -120,7 +135,10 @@ var ToolOperationNames = map[string]string{
 	OpCreate: "create",
 	OpUpdate: "update",
 	OpDelete: "delete",
+	OpLink:   "link",
+	OpUnlink: "unlink",
 	OpInfo:   "info",
+	OpBatch:  "batch",
 }
 
 // Shortened tool operation names
@@ -132,7 +150,10 @@ var ShortenedToolOperationNames = map[string]string{
 	OpCreate: "create",
 	OpUpdate: "upd",
 	OpDelete: "del",
+	OpLink:   "link",
+	OpUnlink: "unlink",
 	OpInfo:   "info",
+	OpBatch:  "batch",
 }
 
 // Error messages
@@ -146,6 +167,7 @@ const (
 	ErrCSRFTokenFailed      = "CSRF token fetch failed"
 	ErrRequestFailed        = "HTTP request failed"
 	ErrResponseParseFailed  = "response parsing failed"
+	ErrConfirmationFailed   = "confirmation failed"
 )
 
 // Default values
@@ -155,6 +177,9 @@ const (
 	DefaultMaxResponseSize    = 10 * 1024 * 1024 // 10MB
 	DefaultMaxItems           = 1000
 	DefaultToolNameMaxLength  = 64
+	DefaultMaxBatchOperations = 100
+	DefaultTokenExpiryMargin  = 60 * time.Second // Renew OAuth2 tokens this long before they expire
+	DefaultConfirmationTTL    = 5 * time.Minute  // Lifetime of write confirmation tokens
 )
 
 // MCP-specific constants
@@ -164,6 +189,13 @@ const (
 	MCPServerVersion   = "1.0.0"
 )
 
+// MCPSupportedProtocolVersions lists the protocol versions the server can negotiate
+var MCPSupportedProtocolVersions = []string{
+	"2025-06-18",
+	"2025-03-26",
+	"2024-11-05",
+}
+
 // GetGoType returns the Go type for an OData type
 func GetGoType(odataType string) string {
 	if goType, ok := ODataTypeMap[odataType]; ok {
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// ErrResourceNotFound is returned by resource handlers for URIs they do not serve
+var ErrResourceNotFound = errors.New("resource not found")
+
+// Resource represents an MCP resource a client can read as context
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceTemplate represents a family of resources addressed by an RFC 6570 URI template
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is the text content of a read resource
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ResourceHandler reads the resource with the given URI
+type ResourceHandler func(ctx context.Context, uri string) (*ResourceContents, error)
+
+// AddResource registers a resource, replacing a resource with the same URI
+func (s *Server) AddResource(resource *Resource, handler ResourceHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.resources[resource.URI]; !exists {
+		s.resourceOrder = append(s.resourceOrder, resource.URI)
+	}
+	s.resources[resource.URI] = resource
+	s.resourceHandlers[resource.URI] = handler
+}
+
+// RemoveResource removes a resource from the server
+func (s *Server) RemoveResource(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.resources, uri)
+	delete(s.resourceHandlers, uri)
+	for i, resourceURI := range s.resourceOrder {
+		if resourceURI == uri {
+			s.resourceOrder = append(s.resourceOrder[:i], s.resourceOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// GetResources returns all registered resources in insertion order
+func (s *Server) GetResources() []*Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resources := make([]*Resource, 0, len(s.resources))
+	for _, uri := range s.resourceOrder {
+		if resource, exists := s.resources[uri]; exists {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// AddResourceTemplate registers a resource template. Reads of URIs that are
+// not registered resources go to the handler of the template whose literal
+// prefix (the part before the first variable) matches the URI.
+func (s *Server) AddResourceTemplate(template *ResourceTemplate, handler ResourceHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.templates {
+		if existing.URITemplate == template.URITemplate {
+			s.templates[i] = template
+			s.templateHandlers[template.URITemplate] = handler
+			return
+		}
+	}
+	s.templates = append(s.templates, template)
+	s.templateHandlers[template.URITemplate] = handler
+}
+
+// resourceHandler returns the handler of a registered resource, or else of the
+// template with the longest literal prefix matching the URI
+func (s *Server) resourceHandler(uri string) (ResourceHandler, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if handler, exists := s.resourceHandlers[uri]; exists {
+		return handler, true
+	}
+
+	var handler ResourceHandler
+	longest := -1
+	for _, template := range s.templates {
+		prefix, _, _ := strings.Cut(template.URITemplate, "{")
+		if strings.HasPrefix(uri, prefix) && len(prefix) > longest {
+			handler, longest = s.templateHandlers[template.URITemplate], len(prefix)
+		}
+	}
+	return handler, handler != nil
+}
+
+// handleResourcesListV2 handles the resources/list request for transport
+func (s *Server) handleResourcesListV2(req *Request) (*transport.Message, error) {
+	result := map[string]interface{}{
+		"resources": s.GetResources(),
+	}
+	return s.createResponse(req.ID, result)
+}
+
+// handleResourceTemplatesListV2 handles the resources/templates/list request for transport
+func (s *Server) handleResourceTemplatesListV2(req *Request) (*transport.Message, error) {
+	s.mu.RLock()
+	templates := append([]*ResourceTemplate{}, s.templates...)
+	s.mu.RUnlock()
+
+	result := map[string]interface{}{
+		"resourceTemplates": templates,
+	}
+	return s.createResponse(req.ID, result)
+}
+
+// handleResourcesReadV2 handles the resources/read request for transport
+func (s *Server) handleResourcesReadV2(ctx context.Context, req *Request) (*transport.Message, error) {
+	uri, ok := req.Params["uri"].(string)
+	if !ok || uri == "" {
+		return s.createErrorResponse(req.ID, -32602, "Invalid params", "Missing resource URI"), nil
+	}
+
+	handler, ok := s.resourceHandler(uri)
+	if !ok {
+		return s.createErrorResponse(req.ID, -32002, "Resource not found", uri), nil
+	}
+
+	contents, err := handler(ctx, uri)
+	if errors.Is(err, ErrResourceNotFound) {
+		return s.createErrorResponse(req.ID, -32002, "Resource not found", uri), nil
+	}
+	if err != nil {
+		return s.createErrorResponse(req.ID, -32603, fmt.Sprintf("Failed to read resource: %v", err), uri), nil
+	}
+
+	result := map[string]interface{}{
+		"contents": []*ResourceContents{contents},
+	}
+	return s.createResponse(req.ID, result)
+}
@@ -18,6 +18,10 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	// ValidateArguments rejects calls whose arguments do not match InputSchema
+	// before the handler runs
+	ValidateArguments bool `json:"-"`
 }
 
 // ToolHandler is a function that handles tool execution
@@ -31,18 +35,34 @@ type Request struct {
 	Params  map[string]interface{} `json:"params,omitempty"`
 }
 
+// Session holds the protocol state negotiated with one client.
+// Session-less transports such as stdio use the session with an empty ID.
+type Session struct {
+	ID              string                 `json:"id"`
+	ProtocolVersion string                 `json:"protocolVersion"`
+	ClientInfo      map[string]interface{} `json:"clientInfo,omitempty"`
+	Initialized     bool                   `json:"initialized"`
+}
+
 // Server represents an MCP server
 type Server struct {
-	name        string
-	version     string
-	tools       map[string]*Tool
-	toolOrder   []string    // Maintains insertion order
-	handlers    map[string]ToolHandler
-	transport   transport.Transport
-	ctx         context.Context
-	cancel      context.CancelFunc
-	mu          sync.RWMutex
-	initialized bool
+	name          string
+	version       string
+	tools         map[string]*Tool
+	toolOrder     []string    // Maintains insertion order
+	handlers      map[string]ToolHandler
+	transport     transport.Transport
+	ctx           context.Context
+	cancel        context.CancelFunc
+	mu            sync.RWMutex
+	sessions      map[string]*Session
+	closeHandlers []func(sessionID string)
+
+	resources        map[string]*Resource
+	resourceOrder    []string // Maintains insertion order
+	resourceHandlers map[string]ResourceHandler
+	templates        []*ResourceTemplate
+	templateHandlers map[string]ResourceHandler
 }
 
 // NewServer creates a new MCP server
@@ -59,6 +79,11 @@ func NewServer(name, version string) *Server {
 		handlers:  make(map[string]ToolHandler),
 		ctx:       ctx,
 		cancel:    cancel,
+		sessions:  make(map[string]*Session),
+
+		resources:        make(map[string]*Resource),
+		resourceHandlers: make(map[string]ResourceHandler),
+		templateHandlers: make(map[string]ResourceHandler),
 	}
 }
 
@@ -112,6 +137,53 @@ func (s *Server) SetTransport(t interface{}) {
 	if trans, ok := t.(transport.Transport); ok {
 		s.transport = trans
 	}
+	if sessionTrans, ok := t.(transport.SessionTransport); ok {
+		sessionTrans.OnSessionClosed(s.closeSession)
+	}
+}
+
+// GetSession returns a copy of the state of a session
+func (s *Server) GetSession(id string) (Session, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, exists := s.sessions[id]
+	if !exists {
+		return Session{}, false
+	}
+	return *session, true
+}
+
+// OnSessionClosed registers a callback that is invoked when a client session ends
+func (s *Server) OnSessionClosed(fn func(sessionID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeHandlers = append(s.closeHandlers, fn)
+}
+
+// session returns the state of a session, creating it on first use
+func (s *Server) session(id string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[id]
+	if !exists {
+		session = &Session{ID: id}
+		s.sessions[id] = session
+	}
+	return session
+}
+
+// closeSession drops the state of a session and notifies close handlers
+func (s *Server) closeSession(id string) {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	handlers := s.closeHandlers
+	s.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(id)
+	}
 }
 
 // Run starts the MCP server
@@ -143,19 +215,28 @@ func (s *Server) HandleMessage(ctx context.Context, msg *transport.Message) (*tr
 	}
 	
 	// Handle notifications (no response expected)
-	if req.Method == "initialized" {
-		s.handleInitialized(req)
+	if req.Method == "initialized" || req.Method == "notifications/initialized" {
+		s.handleInitialized(ctx, req)
+		return nil, nil
+	}
+	if strings.HasPrefix(req.Method, "notifications/") {
 		return nil, nil
 	}
 	
 	// Handle requests
 	switch req.Method {
 	case "initialize":
-		return s.handleInitializeV2(req)
+		return s.handleInitializeV2(ctx, req)
 	case "tools/list":
 		return s.handleToolsListV2(req)
 	case "tools/call":
-		return s.handleToolsCallV2(req)
+		return s.handleToolsCallV2(ctx, req)
+	case "resources/list":
+		return s.handleResourcesListV2(req)
+	case "resources/templates/list":
+		return s.handleResourceTemplatesListV2(req)
+	case "resources/read":
+		return s.handleResourcesReadV2(ctx, req)
 	case "ping":
 		return s.handlePingV2(req)
 	default:
@@ -182,6 +263,28 @@ func (s *Server) createErrorResponse(id interface{}, code int, message, data str
 	}
 }
 
+// createArgumentErrorResponse creates an invalid params response that lists every invalid argument
+func (s *Server) createArgumentErrorResponse(id interface{}, toolName string, argErrs []ArgumentError) *transport.Message {
+	idBytes, _ := json.Marshal(id)
+	details := make([]string, 0, len(argErrs))
+	for _, argErr := range argErrs {
+		details = append(details, fmt.Sprintf("%s %s", argErr.Field, argErr.Message))
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"tool":   toolName,
+		"errors": argErrs,
+	})
+	return &transport.Message{
+		JSONRPC: "2.0",
+		ID:      idBytes,
+		Error: &transport.Error{
+			Code:    -32602,
+			Message: fmt.Sprintf("Invalid arguments for tool '%s': %s", toolName, strings.Join(details, "; ")),
+			Data:    data,
+		},
+	}
+}
+
 // createResponse creates a success response message
 func (s *Server) createResponse(id interface{}, result interface{}) (*transport.Message, error) {
 	idBytes, _ := json.Marshal(id)
@@ -198,13 +301,25 @@ func (s *Server) createResponse(id interface{}, result interface{}) (*transport.
 }
 
 // handleInitializeV2 handles the initialize request for transport
-func (s *Server) handleInitializeV2(req *Request) (*transport.Message, error) {
+func (s *Server) handleInitializeV2(ctx context.Context, req *Request) (*transport.Message, error) {
+	protocolVersion := negotiateProtocolVersion(req.Params)
+
+	session := s.session(transport.SessionIDFromContext(ctx))
+	s.mu.Lock()
+	session.ProtocolVersion = protocolVersion
+	session.Initialized = false
+	if clientInfo, ok := req.Params["clientInfo"].(map[string]interface{}); ok {
+		session.ClientInfo = clientInfo
+	}
+	s.mu.Unlock()
+
 	result := map[string]interface{}{
-		"protocolVersion": constants.MCPProtocolVersion,
+		"protocolVersion": protocolVersion,
 		"capabilities": map[string]interface{}{
 			"tools": map[string]interface{}{
 				"listChanged": true,
 			},
+			"resources": map[string]interface{}{},
 		},
 		"serverInfo": map[string]interface{}{
 			"name":    s.name,
@@ -215,10 +330,22 @@ func (s *Server) handleInitializeV2(req *Request) (*transport.Message, error) {
 	return s.createResponse(req.ID, result)
 }
 
+// negotiateProtocolVersion echoes the client's protocol version if supported, otherwise the default
+func negotiateProtocolVersion(params map[string]interface{}) string {
+	requested, _ := params["protocolVersion"].(string)
+	for _, version := range constants.MCPSupportedProtocolVersions {
+		if version == requested {
+			return requested
+		}
+	}
+	return constants.MCPProtocolVersion
+}
+
 // handleInitialized handles the initialized notification
-func (s *Server) handleInitialized(req *Request) error {
+func (s *Server) handleInitialized(ctx context.Context, req *Request) error {
+	session := s.session(transport.SessionIDFromContext(ctx))
 	s.mu.Lock()
-	s.initialized = true
+	session.Initialized = true
 	s.mu.Unlock()
 	return nil
 }
@@ -243,7 +370,7 @@ func (s *Server) handleToolsListV2(req *Request) (*transport.Message, error) {
 }
 
 // handleToolsCallV2 handles the tools/call request for transport
-func (s *Server) handleToolsCallV2(req *Request) (*transport.Message, error) {
+func (s *Server) handleToolsCallV2(ctx context.Context, req *Request) (*transport.Message, error) {
 	params, ok := req.Params["arguments"].(map[string]interface{})
 	if !ok {
 		params = make(map[string]interface{})
@@ -256,13 +383,20 @@ func (s *Server) handleToolsCallV2(req *Request) (*transport.Message, error) {
 	
 	s.mu.RLock()
 	handler, exists := s.handlers[name]
+	tool := s.tools[name]
 	s.mu.RUnlock()
 	
 	if !exists {
 		return s.createErrorResponse(req.ID, -32602, "Invalid params", fmt.Sprintf("Tool not found: %s", name)), nil
 	}
+
+	if tool.ValidateArguments {
+		if argErrs := ValidateArguments(tool.InputSchema, params); len(argErrs) > 0 {
+			return s.createArgumentErrorResponse(req.ID, name, argErrs), nil
+		}
+	}
 	
-	result, err := handler(s.ctx, params)
+	result, err := handler(ctx, params)
 	if err != nil {
 		// Map OData errors to appropriate MCP error codes and provide detailed context
 		errorCode, errorMessage, errorData := s.categorizeError(err, name)
@@ -307,6 +441,31 @@ func (s *Server) SendNotification(method string, params interface{}) error {
 	return s.transport.WriteMessage(msg)
 }
 
+// Notify sends a notification to the session of ctx, or to all clients when ctx carries no session
+func (s *Server) Notify(ctx context.Context, method string, params interface{}) error {
+	sessionID := transport.SessionIDFromContext(ctx)
+	sessionTrans, ok := s.transport.(transport.SessionTransport)
+	if sessionID == "" || !ok {
+		return s.SendNotification(method, params)
+	}
+
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return sessionTrans.WriteSessionMessage(sessionID, &transport.Message{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  paramsBytes,
+	})
+}
+
+// NotifyToolsListChanged tells all clients that the tool list changed
+func (s *Server) NotifyToolsListChanged() error {
+	return s.SendNotification("notifications/tools/list_changed", map[string]interface{}{})
+}
+
 // categorizeError maps OData errors to appropriate MCP error codes and enhances error messages
 func (s *Server) categorizeError(err error, toolName string) (int, string, string) {
 	errStr := err.Error()
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ArgumentError describes a tool argument that does not match the tool's input schema
+type ArgumentError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidateArguments checks tool call arguments against a tool input schema and
+// returns every violation. It supports the JSON Schema keywords the bridge
+// generates: type, properties, required, additionalProperties, items, enum,
+// minimum, maximum, minLength, maxLength and pattern.
+func ValidateArguments(schema map[string]interface{}, args map[string]interface{}) []ArgumentError {
+	var errs []ArgumentError
+	validateObject("", schema, args, &errs)
+	return errs
+}
+
+// validateValue checks a single value against its schema
+func validateValue(field string, schema map[string]interface{}, value interface{}, errs *[]ArgumentError) {
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(types, value) {
+		*errs = append(*errs, ArgumentError{field, fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), jsonType(value))})
+		return
+	}
+
+	if enum, ok := schema["enum"]; ok && !inEnum(enum, value) {
+		*errs = append(*errs, ArgumentError{field, fmt.Sprintf("must be one of %v", enum)})
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if max, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > max {
+			*errs = append(*errs, ArgumentError{field, fmt.Sprintf("is %d characters long, maximum is %g", length, max)})
+		}
+		if min, ok := schemaNumber(schema, "minLength"); ok && float64(length) < min {
+			*errs = append(*errs, ArgumentError{field, fmt.Sprintf("is %d characters long, minimum is %g", length, min)})
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				*errs = append(*errs, ArgumentError{field, fmt.Sprintf("does not match pattern %s", pattern)})
+			}
+		}
+	case float64:
+		if max, ok := schemaNumber(schema, "maximum"); ok && v > max {
+			*errs = append(*errs, ArgumentError{field, fmt.Sprintf("is %g, maximum is %g", v, max)})
+		}
+		if min, ok := schemaNumber(schema, "minimum"); ok && v < min {
+			*errs = append(*errs, ArgumentError{field, fmt.Sprintf("is %g, minimum is %g", v, min)})
+		}
+	case map[string]interface{}:
+		validateObject(field, schema, v, errs)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", field, i), items, item, errs)
+			}
+		}
+	}
+}
+
+// validateObject checks required, known and nested properties of an object
+func validateObject(field string, schema map[string]interface{}, obj map[string]interface{}, errs *[]ArgumentError) {
+	properties, hasProperties := schema["properties"].(map[string]interface{})
+
+	for _, name := range schemaStrings(schema["required"]) {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, ArgumentError{joinField(field, name), "is required"})
+		}
+	}
+
+	// Report fields in a stable order
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propSchema, known := properties[name].(map[string]interface{})
+		if !known {
+			if hasProperties && schema["additionalProperties"] != true {
+				*errs = append(*errs, ArgumentError{joinField(field, name), "is not a known property"})
+			}
+			continue
+		}
+		validateValue(joinField(field, name), propSchema, obj[name], errs)
+	}
+}
+
+// schemaTypes returns the allowed types of a schema, "type" may be a string or a list
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	default:
+		return schemaStrings(t)
+	}
+}
+
+// schemaStrings converts a []string or []interface{} schema value
+func schemaStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaNumber reads a numeric keyword, whatever numeric type the schema was built with
+func schemaNumber(schema map[string]interface{}, keyword string) (float64, bool) {
+	switch n := schema[keyword].(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func matchesAnyType(types []string, value interface{}) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value, telling integers from other numbers
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(enum interface{}, value interface{}) bool {
+	switch values := enum.(type) {
+	case []string:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if v == s {
+				return true
+			}
+		}
+		return false
+	case []interface{}:
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
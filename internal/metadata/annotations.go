@@ -0,0 +1,296 @@
+package metadata
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// Vocabulary namespaces of the annotations that shape tool generation
+const (
+	capabilitiesNamespace = "Org.OData.Capabilities.V1"
+	coreNamespace         = "Org.OData.Core.V1"
+)
+
+// AnnotationsV4 groups annotations that apply to an external target
+type AnnotationsV4 struct {
+	XMLName     xml.Name       `xml:"Annotations"`
+	Target      string         `xml:"Target,attr"`
+	Qualifier   string         `xml:"Qualifier,attr"`
+	Annotations []AnnotationV4 `xml:"Annotation"`
+}
+
+// AnnotationV4 applies a vocabulary term to a model element
+type AnnotationV4 struct {
+	XMLName    xml.Name  `xml:"Annotation"`
+	Term       string    `xml:"Term,attr"`
+	Qualifier  string    `xml:"Qualifier,attr"`
+	Bool       string    `xml:"Bool,attr"`
+	String     string    `xml:"String,attr"`
+	BoolValue  string    `xml:"Bool"`
+	StringText string    `xml:"String"`
+	Record     *RecordV4 `xml:"Record"`
+}
+
+// RecordV4 is a structured annotation value
+type RecordV4 struct {
+	XMLName        xml.Name          `xml:"Record"`
+	PropertyValues []PropertyValueV4 `xml:"PropertyValue"`
+}
+
+// PropertyValueV4 is a property of a record value
+type PropertyValueV4 struct {
+	XMLName    xml.Name      `xml:"PropertyValue"`
+	Property   string        `xml:"Property,attr"`
+	Bool       string        `xml:"Bool,attr"`
+	BoolValue  string        `xml:"Bool"`
+	Collection *CollectionV4 `xml:"Collection"`
+}
+
+// CollectionV4 is a collection annotation value
+type CollectionV4 struct {
+	XMLName       xml.Name `xml:"Collection"`
+	PropertyPaths []string `xml:"PropertyPath"`
+}
+
+// annotationIndex collects the inline and targeted annotations of a document
+// by model element. Keys are "set:<EntitySet>", "type:<Type>" and
+// "type:<Type>/<Property>".
+type annotationIndex map[string][]AnnotationV4
+
+func setTarget(name string) string                { return "set:" + name }
+func typeTarget(name string) string               { return "type:" + name }
+func propertyTarget(typeName, prop string) string { return "type:" + typeName + "/" + prop }
+
+// add records annotations of a target. Qualified annotations apply to a
+// specific client or context and are ignored.
+func (idx annotationIndex) add(target string, annotations []AnnotationV4) {
+	for _, annotation := range annotations {
+		if annotation.Qualifier == "" {
+			idx[target] = append(idx[target], annotation)
+		}
+	}
+}
+
+// collectAnnotationsV4 indexes the annotations of all schemas: inline on
+// entity types, properties, entity sets and singletons, and in Annotations
+// elements targeting them by path
+func collectAnnotationsV4(schemas []SchemaV4, resolver *typeResolver) annotationIndex {
+	idx := make(annotationIndex)
+	containers := make(map[string]bool)
+	for _, schema := range schemas {
+		for _, et := range schema.EntityTypes {
+			idx.add(typeTarget(et.Name), et.Annotations)
+			for _, prop := range et.Properties {
+				idx.add(propertyTarget(et.Name, prop.Name), prop.Annotations)
+			}
+		}
+		for _, ct := range schema.ComplexTypes {
+			for _, prop := range ct.Properties {
+				idx.add(propertyTarget(ct.Name, prop.Name), prop.Annotations)
+			}
+		}
+		for _, container := range schema.EntityContainers {
+			containers[container.Name] = true
+			for _, es := range container.EntitySets {
+				idx.add(setTarget(es.Name), es.Annotations)
+			}
+			for _, st := range container.Singletons {
+				idx.add(setTarget(st.Name), st.Annotations)
+			}
+		}
+	}
+
+	for _, schema := range schemas {
+		for _, group := range schema.Annotations {
+			if group.Qualifier != "" {
+				continue
+			}
+			if target, ok := annotationTarget(group.Target, resolver, containers); ok {
+				idx.add(target, group.Annotations)
+			}
+		}
+	}
+	return idx
+}
+
+// annotationTarget converts the path of an Annotations element to an index
+// key. Paths name a type ("NS.Type"), a property ("NS.Type/Property") or an
+// entity set or singleton ("NS.Container/EntitySet").
+func annotationTarget(path string, resolver *typeResolver, containers map[string]bool) (string, bool) {
+	head, rest := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		head, rest = path[:i], path[i+1:]
+	}
+	if strings.Contains(rest, "/") {
+		return "", false
+	}
+
+	if typeName, ok := resolver.resolve(head); ok {
+		if rest == "" {
+			return typeTarget(typeName), true
+		}
+		return propertyTarget(typeName, rest), true
+	}
+	if containers[unqualified(head)] && rest != "" {
+		return setTarget(rest), true
+	}
+	return "", false
+}
+
+// applyAnnotationsV4 applies the Capabilities and Core annotations of the
+// index to the entity sets, singletons, entity types and properties
+func applyAnnotationsV4(metadata *models.ODataMetadata, idx annotationIndex, resolver *typeResolver) {
+	for name, entitySet := range metadata.EntitySets {
+		for _, annotation := range idx[setTarget(name)] {
+			applyEntitySetAnnotation(entitySet, annotation, resolver)
+		}
+	}
+	for name, singleton := range metadata.Singletons {
+		for _, annotation := range idx[setTarget(name)] {
+			switch resolver.qualifiedTerm(annotation.Term) {
+			case capabilitiesNamespace + ".UpdateRestrictions":
+				singleton.Updatable = recordBool(annotation, "Updatable", singleton.Updatable)
+			case coreNamespace + ".Description":
+				singleton.Description = annotationString(annotation)
+			}
+		}
+	}
+	for name, entityType := range metadata.EntityTypes {
+		for _, annotation := range idx[typeTarget(name)] {
+			if resolver.qualifiedTerm(annotation.Term) == coreNamespace+".Description" {
+				entityType.Description = annotationString(annotation)
+			}
+		}
+		applyPropertyAnnotations(name, entityType.Properties, idx, resolver)
+	}
+	for name, complexType := range metadata.ComplexTypes {
+		applyPropertyAnnotations(name, complexType.Properties, idx, resolver)
+	}
+}
+
+// applyEntitySetAnnotation applies a Capabilities restriction or Core
+// description to an entity set
+func applyEntitySetAnnotation(entitySet *models.EntitySet, annotation AnnotationV4, resolver *typeResolver) {
+	switch resolver.qualifiedTerm(annotation.Term) {
+	case capabilitiesNamespace + ".InsertRestrictions":
+		entitySet.Creatable = recordBool(annotation, "Insertable", entitySet.Creatable)
+	case capabilitiesNamespace + ".UpdateRestrictions":
+		entitySet.Updatable = recordBool(annotation, "Updatable", entitySet.Updatable)
+	case capabilitiesNamespace + ".DeleteRestrictions":
+		entitySet.Deletable = recordBool(annotation, "Deletable", entitySet.Deletable)
+	case capabilitiesNamespace + ".SearchRestrictions":
+		entitySet.Searchable = recordBool(annotation, "Searchable", entitySet.Searchable)
+	case capabilitiesNamespace + ".CountRestrictions":
+		entitySet.Countable = recordBool(annotation, "Countable", entitySet.Countable)
+	case capabilitiesNamespace + ".FilterRestrictions":
+		entitySet.Filterable = recordBool(annotation, "Filterable", entitySet.Filterable)
+		entitySet.RequiresFilter = recordBool(annotation, "RequiresFilter", entitySet.RequiresFilter)
+		entitySet.RequiredFilterProperties = recordPaths(annotation, "RequiredProperties")
+		entitySet.NonFilterableProperties = recordPaths(annotation, "NonFilterableProperties")
+	case capabilitiesNamespace + ".TopSupported":
+		entitySet.TopSupported = annotationBool(annotation)
+	case coreNamespace + ".Description":
+		entitySet.Description = annotationString(annotation)
+	}
+}
+
+// applyPropertyAnnotations applies Core descriptions and Core.Computed and
+// Core.Immutable to the properties of a type
+func applyPropertyAnnotations(typeName string, properties []*models.EntityProperty, idx annotationIndex, resolver *typeResolver) {
+	for _, prop := range properties {
+		for _, annotation := range idx[propertyTarget(typeName, prop.Name)] {
+			switch resolver.qualifiedTerm(annotation.Term) {
+			case coreNamespace + ".Description":
+				prop.Description = annotationString(annotation)
+			case coreNamespace + ".Computed":
+				// Computed values are set by the service and cannot be written
+				if annotationBool(annotation) {
+					prop.Creatable = false
+					prop.Updatable = false
+				}
+			case coreNamespace + ".Immutable":
+				// Immutable values can be set on create but not changed
+				if annotationBool(annotation) {
+					prop.Updatable = false
+				}
+			}
+		}
+	}
+}
+
+// qualifiedTerm replaces the alias of a term with its vocabulary namespace
+func (r *typeResolver) qualifiedTerm(term string) string {
+	i := strings.LastIndex(term, ".")
+	if i < 0 {
+		return term
+	}
+	if namespace, ok := r.aliases[term[:i]]; ok {
+		return namespace + term[i:]
+	}
+	return term
+}
+
+// annotationBool returns the value of a Boolean term. A term without a value
+// is a tag and means true.
+func annotationBool(annotation AnnotationV4) bool {
+	value := annotation.Bool
+	if value == "" {
+		value = strings.TrimSpace(annotation.BoolValue)
+	}
+	return value != "false"
+}
+
+// annotationString returns the value of a String term, nil when it is empty
+func annotationString(annotation AnnotationV4) *string {
+	value := annotation.String
+	if value == "" {
+		value = strings.TrimSpace(annotation.StringText)
+	}
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// recordBool returns a Boolean property of a record value or the fallback
+// when the record does not set it
+func recordBool(annotation AnnotationV4, property string, fallback bool) bool {
+	if annotation.Record == nil {
+		return fallback
+	}
+	for _, pv := range annotation.Record.PropertyValues {
+		if pv.Property != property {
+			continue
+		}
+		value := pv.Bool
+		if value == "" {
+			value = strings.TrimSpace(pv.BoolValue)
+		}
+		switch value {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return fallback
+}
+
+// recordPaths returns the property paths of a collection property of a record value
+func recordPaths(annotation AnnotationV4, property string) []string {
+	if annotation.Record == nil {
+		return nil
+	}
+	for _, pv := range annotation.Record.PropertyValues {
+		if pv.Property == property && pv.Collection != nil {
+			paths := make([]string, 0, len(pv.Collection.PropertyPaths))
+			for _, path := range pv.Collection.PropertyPaths {
+				paths = append(paths, strings.TrimSpace(path))
+			}
+			return paths
+		}
+	}
+	return nil
+}
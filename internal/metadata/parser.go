@@ -3,6 +3,7 @@ package metadata
 import (
 	"encoding/xml"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,22 +15,25 @@ import (
 type EDMX struct {
 	XMLName   xml.Name `xml:"Edmx"`
 	Version   string   `xml:"Version,attr"`
+	References []Reference `xml:"Reference"`
 	DataServices DataServices `xml:"DataServices"`
 }
 
-// DataServices contains the schema
+// DataServices contains the schemas
 type DataServices struct {
 	XMLName xml.Name `xml:"DataServices"`
-	Schema  Schema   `xml:"Schema"`
+	Schemas []Schema `xml:"Schema"`
 }
 
 // Schema contains entity types, entity sets, and function imports
 type Schema struct {
 	XMLName           xml.Name           `xml:"Schema"`
 	Namespace         string             `xml:"Namespace,attr"`
+	Alias             string             `xml:"Alias,attr"`
 	EntityTypes       []EntityType       `xml:"EntityType"`
-	EntityContainer   EntityContainer    `xml:"EntityContainer"`
+	EntityContainers  []EntityContainer  `xml:"EntityContainer"`
 	FunctionImports   []FunctionImport   `xml:"FunctionImport"`
+	Associations      []Association      `xml:"Association"`
 }
 
 // EntityType represents an OData entity type
@@ -62,6 +66,15 @@ type Property struct {
 	MaxLength  string   `xml:"MaxLength,attr"`
 	Precision  string   `xml:"Precision,attr"`
 	Scale      string   `xml:"Scale,attr"`
+	// SAP-specific attributes
+	Label            string `xml:"label,attr"`
+	Filterable       string `xml:"filterable,attr"`
+	Sortable         string `xml:"sortable,attr"`
+	Creatable        string `xml:"creatable,attr"`
+	Updatable        string `xml:"updatable,attr"`
+	RequiredInFilter string `xml:"required-in-filter,attr"`
+	DisplayFormat    string `xml:"display-format,attr"`
+	Text             string `xml:"text,attr"`
 }
 
 // NavigationProperty represents a navigation property
@@ -73,12 +86,44 @@ type NavigationProperty struct {
 	FromRole     string   `xml:"FromRole,attr"`
 }
 
+// Association represents a relationship between two entity types
+type Association struct {
+	XMLName xml.Name         `xml:"Association"`
+	Name    string           `xml:"Name,attr"`
+	Ends    []AssociationEnd `xml:"End"`
+}
+
+// AssociationEnd is one side of an association
+type AssociationEnd struct {
+	XMLName      xml.Name `xml:"End"`
+	Role         string   `xml:"Role,attr"`
+	Type         string   `xml:"Type,attr"`
+	Multiplicity string   `xml:"Multiplicity,attr"` // "1", "0..1" or "*"
+}
+
+// AssociationSet relates the entity sets of the two ends of an association
+type AssociationSet struct {
+	XMLName     xml.Name            `xml:"AssociationSet"`
+	Name        string              `xml:"Name,attr"`
+	Association string              `xml:"Association,attr"`
+	Ends        []AssociationSetEnd `xml:"End"`
+}
+
+// AssociationSetEnd names the entity set of an association role
+type AssociationSetEnd struct {
+	XMLName   xml.Name `xml:"End"`
+	Role      string   `xml:"Role,attr"`
+	EntitySet string   `xml:"EntitySet,attr"`
+}
+
 // EntityContainer contains entity sets and function imports
 type EntityContainer struct {
 	XMLName         xml.Name         `xml:"EntityContainer"`
 	Name            string           `xml:"Name,attr"`
+	IsDefault       string           `xml:"IsDefaultEntityContainer,attr"` // m:IsDefaultEntityContainer
 	EntitySets      []EntitySet      `xml:"EntitySet"`
 	FunctionImports []FunctionImport `xml:"FunctionImport"`
+	AssociationSets []AssociationSet `xml:"AssociationSet"`
 }
 
 // EntitySet represents an OData entity set
@@ -99,7 +144,7 @@ type FunctionImport struct {
 	XMLName    xml.Name    `xml:"FunctionImport"`
 	Name       string      `xml:"Name,attr"`
 	ReturnType string      `xml:"ReturnType,attr"`
-	HTTPMethod string      `xml:"m:HttpMethod,attr"`
+	HTTPMethod string      `xml:"HttpMethod,attr"`
 	Parameters []Parameter `xml:"Parameter"`
 }
 
@@ -126,40 +171,222 @@ func ParseMetadata(data []byte, serviceRoot string) (*models.ODataMetadata, erro
 		return nil, fmt.Errorf("failed to parse metadata XML: %w", err)
 	}
 
-	schema := edmx.DataServices.Schema
-	
+	if len(edmx.DataServices.Schemas) == 0 {
+		return nil, fmt.Errorf("no schemas found in metadata")
+	}
+
+	// Types and containers may be split across schemas. The default container
+	// names the service, entity sets of all containers are exposed.
+	schema, container := defaultContainer(edmx.DataServices.Schemas)
+
 	metadata := &models.ODataMetadata{
 		ServiceRoot:     serviceRoot,
 		EntityTypes:     make(map[string]*models.EntityType),
 		EntitySets:      make(map[string]*models.EntitySet),
 		FunctionImports: make(map[string]*models.FunctionImport),
 		SchemaNamespace: schema.Namespace,
-		ContainerName:   schema.EntityContainer.Name,
+		ContainerName:   container.Name,
 		Version:         edmx.Version,
 		ParsedAt:        time.Now(),
 	}
 
-	// Parse entity types
-	for _, et := range schema.EntityTypes {
-		entityType := parseEntityType(et)
-		metadata.EntityTypes[et.Name] = entityType
+	resolver := newTypeResolver()
+	metadata.References = parseReferences(edmx.References, resolver)
+
+	// Register the types of all schemas first, a name defined in several
+	// namespaces is keyed by its qualified name
+	for _, schema := range edmx.DataServices.Schemas {
+		resolver.addNamespace(schema.Namespace, schema.Alias)
+		for _, et := range schema.EntityTypes {
+			resolver.addType(schema.Namespace, et.Name)
+		}
+	}
+
+	// Parse entity types from all schemas
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, et := range schema.EntityTypes {
+			entityType := parseEntityType(et)
+			metadata.EntityTypes[resolver.key(schema.Namespace, et.Name)] = entityType
+		}
+	}
+
+	associations := make([]Association, 0)
+	for _, schema := range edmx.DataServices.Schemas {
+		associations = append(associations, schema.Associations...)
 	}
 
-	// Parse entity sets
-	for _, es := range schema.EntityContainer.EntitySets {
-		entitySet := parseEntitySet(es, schema.Namespace)
-		metadata.EntitySets[es.Name] = entitySet
+	associationSets := make([]AssociationSet, 0)
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, container := range schema.EntityContainers {
+			// Parse entity sets
+			for _, es := range container.EntitySets {
+				entitySet := parseEntitySet(es, schema.Namespace)
+				entitySet.EntityType = resolver.entityTypeName(es.EntityType)
+				metadata.EntitySets[es.Name] = entitySet
+			}
+
+			// Parse function imports
+			for _, fi := range container.FunctionImports {
+				functionImport := parseFunctionImport(fi)
+				metadata.FunctionImports[fi.Name] = functionImport
+			}
+
+			associationSets = append(associationSets, container.AssociationSets...)
+		}
 	}
 
-	// Parse function imports
-	for _, fi := range schema.EntityContainer.FunctionImports {
-		functionImport := parseFunctionImport(fi)
-		metadata.FunctionImports[fi.Name] = functionImport
+	// Parse associations and association sets
+	metadata.Associations = make(map[string]*models.Association)
+	for _, assoc := range associations {
+		metadata.Associations[assoc.Name] = parseAssociation(assoc)
 	}
+	metadata.AssociationSets = make(map[string]*models.AssociationSet)
+	for _, assocSet := range associationSets {
+		metadata.AssociationSets[assocSet.Name] = parseAssociationSet(assocSet)
+	}
+
+	// Resolve navigation properties through their associations
+	resolveNavigation(metadata)
 
 	return metadata, nil
 }
 
+// defaultContainer returns the entity container marked as default, or the
+// first one, and its schema
+func defaultContainer(schemas []Schema) (Schema, EntityContainer) {
+	var first *Schema
+	for i := range schemas {
+		for _, container := range schemas[i].EntityContainers {
+			if container.IsDefault == "true" {
+				return schemas[i], container
+			}
+			if first == nil {
+				first = &schemas[i]
+			}
+		}
+	}
+	if first == nil {
+		return schemas[0], EntityContainer{}
+	}
+	return *first, first.EntityContainers[0]
+}
+
+// parseAssociation converts XML association to model
+func parseAssociation(assoc Association) *models.Association {
+	association := &models.Association{
+		Name: assoc.Name,
+		Ends: make([]*models.AssociationEnd, 0, len(assoc.Ends)),
+	}
+	for _, end := range assoc.Ends {
+		association.Ends = append(association.Ends, &models.AssociationEnd{
+			Role:         end.Role,
+			EntityType:   unqualified(end.Type),
+			Multiplicity: end.Multiplicity,
+		})
+	}
+	return association
+}
+
+// parseAssociationSet converts XML association set to model
+func parseAssociationSet(assocSet AssociationSet) *models.AssociationSet {
+	associationSet := &models.AssociationSet{
+		Name:        assocSet.Name,
+		Association: unqualified(assocSet.Association),
+		Ends:        make([]*models.AssociationSetEnd, 0, len(assocSet.Ends)),
+	}
+	for _, end := range assocSet.Ends {
+		associationSet.Ends = append(associationSet.Ends, &models.AssociationSetEnd{
+			Role:      end.Role,
+			EntitySet: end.EntitySet,
+		})
+	}
+	return associationSet
+}
+
+// resolveNavigation sets the target type and multiplicity of v2 navigation
+// properties from their association, and the target entity set of each
+// navigation property from the association sets
+func resolveNavigation(metadata *models.ODataMetadata) {
+	for _, entityType := range metadata.EntityTypes {
+		for _, nav := range entityType.NavigationProps {
+			assoc, ok := metadata.Associations[unqualified(nav.Relationship)]
+			if !ok {
+				continue
+			}
+			for _, end := range assoc.Ends {
+				if end.Role == nav.ToRole {
+					nav.TargetType = end.EntityType
+					nav.Multiplicity = end.Multiplicity
+					nav.IsCollection = end.Multiplicity == "*"
+				}
+			}
+		}
+	}
+
+	for _, entitySet := range metadata.EntitySets {
+		entityType, ok := metadata.EntityTypes[entitySet.EntityType]
+		if !ok {
+			continue
+		}
+		for _, nav := range entityType.NavigationProps {
+			for _, assocSet := range metadata.AssociationSets {
+				if assocSet.Association != unqualified(nav.Relationship) {
+					continue
+				}
+				from, to := "", ""
+				for _, end := range assocSet.Ends {
+					switch end.Role {
+					case nav.FromRole:
+						from = end.EntitySet
+					case nav.ToRole:
+						to = end.EntitySet
+					}
+				}
+				if from == entitySet.Name && to != "" {
+					if entitySet.NavigationTargets == nil {
+						entitySet.NavigationTargets = make(map[string]string)
+					}
+					entitySet.NavigationTargets[nav.Name] = to
+				}
+			}
+		}
+	}
+
+	setNavigationTargetSets(metadata)
+}
+
+// setNavigationTargetSets records the target entity set on navigation
+// properties whose entity sets all lead to the same target
+func setNavigationTargetSets(metadata *models.ODataMetadata) {
+	for typeName, entityType := range metadata.EntityTypes {
+		for _, nav := range entityType.NavigationProps {
+			target, consistent := "", true
+			for _, entitySet := range metadata.EntitySets {
+				if entitySet.EntityType != typeName {
+					continue
+				}
+				setTarget := entitySet.NavigationTargets[nav.Name]
+				if setTarget == "" || (target != "" && setTarget != target) {
+					consistent = false
+					break
+				}
+				target = setTarget
+			}
+			if consistent {
+				nav.TargetSet = target
+			}
+		}
+	}
+}
+
+// unqualified strips the namespace from a qualified name
+func unqualified(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
 // parseEntityType converts XML entity type to model
 func parseEntityType(et EntityType) *models.EntityType {
 	entityType := &models.EntityType{
@@ -177,10 +404,25 @@ func parseEntityType(et EntityType) *models.EntityType {
 	// Parse properties
 	for _, prop := range et.Properties {
 		property := &models.EntityProperty{
-			Name:     prop.Name,
-			Type:     prop.Type,
-			Nullable: prop.Nullable != "false", // Default to true if not specified
-			IsKey:    contains(entityType.KeyProperties, prop.Name),
+			Name:      prop.Name,
+			Type:      prop.Type,
+			Nullable:  prop.Nullable != "false", // Default to true if not specified
+			IsKey:     contains(entityType.KeyProperties, prop.Name),
+			MaxLength: parseFacet(prop.MaxLength),
+			Precision: parseFacet(prop.Precision),
+			Scale:     parseScale(prop.Scale),
+
+			Filterable:       prop.Filterable != "false", // Default to true
+			Sortable:         prop.Sortable != "false",   // Default to true
+			Creatable:        prop.Creatable != "false",  // Default to true
+			Updatable:        prop.Updatable != "false",  // Default to true
+			RequiredInFilter: prop.RequiredInFilter == "true",
+			DisplayFormat:    prop.DisplayFormat,
+			Text:             prop.Text,
+		}
+		if prop.Label != "" {
+			label := prop.Label
+			property.Description = &label
 		}
 		entityType.Properties = append(entityType.Properties, property)
 	}
@@ -216,6 +458,10 @@ func parseEntitySet(es EntitySet, namespace string) *models.EntitySet {
 		Deletable:  es.Deletable != "false", // Default to true
 		Searchable: es.Searchable == "true",  // Default to false
 		Pageable:   es.Pageable != "false",   // Default to true
+
+		Filterable:   true,
+		Countable:    true,
+		TopSupported: true,
 	}
 
 	return entitySet
@@ -258,6 +504,25 @@ func parseFunctionImport(fi FunctionImport) *models.FunctionImport {
 	return functionImport
 }
 
+// parseFacet converts a numeric facet such as MaxLength or Precision.
+// Absent and non-numeric values ("Max") give 0.
+func parseFacet(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseScale converts the Scale facet, returning -1 for "variable" (v4) or "floating"
+func parseScale(value string) int {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "variable", "floating":
+		return -1
+	}
+	return parseFacet(value)
+}
+
 // contains checks if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -3,6 +3,7 @@ package metadata
 import (
 	"encoding/xml"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 type EDMXV4 struct {
 	XMLName      xml.Name       `xml:"Edmx"`
 	Version      string         `xml:"Version,attr"`
+	References   []Reference    `xml:"Reference"`
 	DataServices DataServicesV4 `xml:"DataServices"`
 }
 
@@ -26,12 +28,14 @@ type DataServicesV4 struct {
 type SchemaV4 struct {
 	XMLName          xml.Name           `xml:"Schema"`
 	Namespace        string             `xml:"Namespace,attr"`
+	Alias            string             `xml:"Alias,attr"`
 	EntityTypes      []EntityTypeV4     `xml:"EntityType"`
 	ComplexTypes     []ComplexTypeV4    `xml:"ComplexType"`
 	EnumTypes        []EnumTypeV4       `xml:"EnumType"`
 	EntityContainers []EntityContainerV4 `xml:"EntityContainer"`
 	Functions        []FunctionV4        `xml:"Function"`
 	Actions          []ActionV4          `xml:"Action"`
+	Annotations      []AnnotationsV4     `xml:"Annotations"`
 }
 
 // EntityTypeV4 represents an OData v4 entity type
@@ -44,6 +48,7 @@ type EntityTypeV4 struct {
 	Key                  KeyV4                  `xml:"Key"`
 	Properties           []PropertyV4           `xml:"Property"`
 	NavigationProperties []NavigationPropertyV4 `xml:"NavigationProperty"`
+	Annotations          []AnnotationV4         `xml:"Annotation"`
 }
 
 // ComplexTypeV4 represents an OData v4 complex type
@@ -96,6 +101,7 @@ type PropertyV4 struct {
 	Scale         string   `xml:"Scale,attr"`
 	Unicode       string   `xml:"Unicode,attr"`
 	DefaultValue  string   `xml:"DefaultValue,attr"`
+	Annotations   []AnnotationV4 `xml:"Annotation"`
 }
 
 // NavigationPropertyV4 represents a navigation property in OData v4
@@ -125,6 +131,7 @@ type EntitySetV4 struct {
 	Name                     string                       `xml:"Name,attr"`
 	EntityType               string                       `xml:"EntityType,attr"`
 	NavigationPropertyBindings []NavigationPropertyBinding `xml:"NavigationPropertyBinding"`
+	Annotations              []AnnotationV4               `xml:"Annotation"`
 }
 
 // SingletonV4 represents an OData v4 singleton
@@ -133,6 +140,7 @@ type SingletonV4 struct {
 	Name                     string                       `xml:"Name,attr"`
 	Type                     string                       `xml:"Type,attr"`
 	NavigationPropertyBindings []NavigationPropertyBinding `xml:"NavigationPropertyBinding"`
+	Annotations              []AnnotationV4               `xml:"Annotation"`
 }
 
 // NavigationPropertyBinding represents a navigation property binding
@@ -226,45 +234,236 @@ func ParseMetadataV4(data []byte, serviceRoot string) (*models.ODataMetadata, er
 		EntityTypes:     make(map[string]*models.EntityType),
 		EntitySets:      make(map[string]*models.EntitySet),
 		FunctionImports: make(map[string]*models.FunctionImport),
+		Singletons:      make(map[string]*models.Singleton),
+		ComplexTypes:    make(map[string]*models.ComplexType),
+		EnumTypes:       make(map[string]*models.EnumType),
 		SchemaNamespace: mainSchema.Namespace,
 		ContainerName:   mainContainer.Name,
 		Version:         edmx.Version,
 		ParsedAt:        time.Now(),
 	}
 
+	resolver := newTypeResolver()
+	metadata.References = parseReferences(edmx.References, resolver)
+
+	registerTypesV4(edmx.DataServices.Schemas, resolver)
+
 	// Parse entity types from all schemas
 	for _, schema := range edmx.DataServices.Schemas {
 		for _, et := range schema.EntityTypes {
 			entityType := parseEntityTypeV4(et)
-			metadata.EntityTypes[et.Name] = entityType
+			qualifyPropertyTypes(entityType.Properties, et.Properties, resolver)
+			metadata.EntityTypes[resolver.key(schema.Namespace, et.Name)] = entityType
 		}
 	}
 
-	// Parse entity sets
-	for _, es := range mainContainer.EntitySets {
-		entitySet := parseEntitySetV4(es, mainSchema.Namespace)
-		metadata.EntitySets[es.Name] = entitySet
+	// Parse complex and enum types from all schemas
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			complexType := parseComplexTypeV4(ct)
+			qualifyPropertyTypes(complexType.Properties, ct.Properties, resolver)
+			metadata.ComplexTypes[resolver.key(schema.Namespace, ct.Name)] = complexType
+		}
+		for _, et := range schema.EnumTypes {
+			metadata.EnumTypes[resolver.key(schema.Namespace, et.Name)] = parseEnumTypeV4(et)
+		}
 	}
+	inheritComplexProperties(edmx.DataServices.Schemas, metadata.ComplexTypes, resolver)
 
-	// Parse function imports
-	for _, fi := range mainContainer.FunctionImports {
-		functionImport := parseFunctionImportV4(fi, mainSchema.Functions)
-		if functionImport != nil {
-			metadata.FunctionImports[fi.Name] = functionImport
+	// Functions and actions may be defined in any schema
+	functions := make([]FunctionV4, 0)
+	actions := make([]ActionV4, 0)
+	for _, schema := range edmx.DataServices.Schemas {
+		functions = append(functions, schema.Functions...)
+		actions = append(actions, schema.Actions...)
+	}
+
+	// The main container includes the entity sets and imports of the containers it extends
+	for _, container := range containerChain(edmx.DataServices.Schemas, mainContainer) {
+		// Parse entity sets
+		for _, es := range container.EntitySets {
+			entitySet := parseEntitySetV4(es, mainSchema.Namespace)
+			entitySet.EntityType = resolver.entityTypeName(es.EntityType)
+			metadata.EntitySets[es.Name] = entitySet
+		}
+
+		// Parse singletons
+		for _, st := range container.Singletons {
+			singleton := parseSingletonV4(st)
+			singleton.EntityType = resolver.entityTypeName(st.Type)
+			metadata.Singletons[st.Name] = singleton
+		}
+
+		// Parse function imports
+		for _, fi := range container.FunctionImports {
+			functionImport := parseFunctionImportV4(fi, functions)
+			if functionImport != nil {
+				metadata.FunctionImports[fi.Name] = functionImport
+			}
+		}
+
+		// Parse action imports as function imports (for compatibility)
+		for _, ai := range container.ActionImports {
+			actionImport := parseActionImportV4(ai, actions)
+			if actionImport != nil {
+				metadata.FunctionImports[ai.Name] = actionImport
+			}
 		}
 	}
 
-	// Parse action imports as function imports (for compatibility)
-	for _, ai := range mainContainer.ActionImports {
-		actionImport := parseActionImportV4(ai, mainSchema.Actions)
-		if actionImport != nil {
-			metadata.FunctionImports[ai.Name] = actionImport
+	// Parse bound functions and actions, which are called on an entity or entity set
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, fn := range schema.Functions {
+			if fn.IsBound == "true" {
+				if bound := parseBoundFunctionV4(fn, schema.Namespace); bound != nil {
+					metadata.BoundOperations = append(metadata.BoundOperations, bound)
+				}
+			}
+		}
+		for _, action := range schema.Actions {
+			if action.IsBound == "true" {
+				if bound := parseBoundActionV4(action, schema.Namespace); bound != nil {
+					metadata.BoundOperations = append(metadata.BoundOperations, bound)
+				}
+			}
 		}
 	}
 
+	// Capabilities and Core annotations restrict sets and describe types and properties
+	applyAnnotationsV4(metadata, collectAnnotationsV4(edmx.DataServices.Schemas, resolver), resolver)
+
+	// Record navigation targets that are the same for all entity sets of a type
+	setNavigationTargetSets(metadata)
+
 	return metadata, nil
 }
 
+// parsePropertiesV4 converts the properties of an entity or complex type
+func parsePropertiesV4(props []PropertyV4, keys []string) []*models.EntityProperty {
+	properties := make([]*models.EntityProperty, 0, len(props))
+	for _, prop := range props {
+		properties = append(properties, &models.EntityProperty{
+			Name:      prop.Name,
+			Type:      normalizeTypeV4(prop.Type),
+			Nullable:  prop.Nullable != "false",
+			IsKey:     contains(keys, prop.Name),
+			MaxLength: parseFacet(prop.MaxLength),
+			Precision: parseFacet(prop.Precision),
+			Scale:     parseScale(prop.Scale),
+
+			Filterable: true,
+			Sortable:   true,
+			Creatable:  true,
+			Updatable:  true,
+		})
+	}
+	return properties
+}
+
+// parseComplexTypeV4 converts XML complex type to model
+func parseComplexTypeV4(ct ComplexTypeV4) *models.ComplexType {
+	return &models.ComplexType{
+		Name:       ct.Name,
+		Properties: parsePropertiesV4(ct.Properties, nil),
+	}
+}
+
+// registerTypesV4 registers the namespaces and the entity, complex and enum
+// types of the schemas
+func registerTypesV4(schemas []SchemaV4, resolver *typeResolver) {
+	for _, schema := range schemas {
+		resolver.addNamespace(schema.Namespace, schema.Alias)
+		for _, et := range schema.EntityTypes {
+			resolver.addType(schema.Namespace, et.Name)
+		}
+		for _, ct := range schema.ComplexTypes {
+			resolver.addType(schema.Namespace, ct.Name)
+		}
+		for _, et := range schema.EnumTypes {
+			resolver.addType(schema.Namespace, et.Name)
+		}
+	}
+}
+
+// inheritComplexProperties prepends the properties of base types to derived complex types
+func inheritComplexProperties(schemas []SchemaV4, complexTypes map[string]*models.ComplexType, resolver *typeResolver) {
+	baseTypes := make(map[string]string)
+	for _, schema := range schemas {
+		for _, ct := range schema.ComplexTypes {
+			if ct.BaseType != "" {
+				baseType, ok := resolver.resolve(ct.BaseType)
+				if !ok {
+					baseType = normalizeTypeV4(ct.BaseType)
+				}
+				baseTypes[resolver.key(schema.Namespace, ct.Name)] = baseType
+			}
+		}
+	}
+
+	// Resolve from the original declarations so the order of types does not matter
+	declared := make(map[string][]*models.EntityProperty, len(complexTypes))
+	for name, ct := range complexTypes {
+		declared[name] = ct.Properties
+	}
+	for name, ct := range complexTypes {
+		properties := declared[name]
+		seen := map[string]bool{name: true}
+		for base := baseTypes[name]; base != "" && !seen[base]; base = baseTypes[base] {
+			seen[base] = true
+			properties = append(append([]*models.EntityProperty{}, declared[base]...), properties...)
+		}
+		ct.Properties = properties
+	}
+}
+
+// parseEnumTypeV4 converts XML enum type to model. Members without a value
+// are numbered from 0, or as powers of two for flags.
+func parseEnumTypeV4(et EnumTypeV4) *models.EnumType {
+	enumType := &models.EnumType{
+		Name:           et.Name,
+		UnderlyingType: et.UnderlyingType,
+		IsFlags:        et.IsFlags == "true",
+		Members:        make([]*models.EnumMember, 0, len(et.Members)),
+	}
+	if enumType.UnderlyingType == "" {
+		enumType.UnderlyingType = "Edm.Int32"
+	}
+
+	for i, member := range et.Members {
+		value, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			value = int64(i)
+			if enumType.IsFlags {
+				value = int64(1) << i
+			}
+		}
+		enumType.Members = append(enumType.Members, &models.EnumMember{Name: member.Name, Value: value})
+	}
+	return enumType
+}
+
+// containerChain returns the containers extended by the main container, base
+// containers first, followed by the main container itself
+func containerChain(schemas []SchemaV4, main *EntityContainerV4) []*EntityContainerV4 {
+	chain := []*EntityContainerV4{main}
+	for current := main; current.Extends != "" && len(chain) <= len(schemas); {
+		var base *EntityContainerV4
+		for i := range schemas {
+			for j := range schemas[i].EntityContainers {
+				if schemas[i].EntityContainers[j].Name == unqualified(current.Extends) {
+					base = &schemas[i].EntityContainers[j]
+				}
+			}
+		}
+		if base == nil {
+			break
+		}
+		chain = append([]*EntityContainerV4{base}, chain...)
+		current = base
+	}
+	return chain
+}
+
 // parseEntityTypeV4 converts XML entity type to model for OData v4
 func parseEntityTypeV4(et EntityTypeV4) *models.EntityType {
 	entityType := &models.EntityType{
@@ -280,23 +479,27 @@ func parseEntityTypeV4(et EntityTypeV4) *models.EntityType {
 	}
 
 	// Parse properties
-	for _, prop := range et.Properties {
-		property := &models.EntityProperty{
-			Name:     prop.Name,
-			Type:     normalizeTypeV4(prop.Type),
-			Nullable: prop.Nullable != "false",
-			IsKey:    contains(entityType.KeyProperties, prop.Name),
-		}
-		entityType.Properties = append(entityType.Properties, property)
-	}
+	entityType.Properties = parsePropertiesV4(et.Properties, entityType.KeyProperties)
 
 	// Parse navigation properties
 	for _, navProp := range et.NavigationProperties {
+		targetType, isCollection := navProp.Type, false
+		if strings.HasPrefix(targetType, "Collection(") && strings.HasSuffix(targetType, ")") {
+			targetType, isCollection = targetType[len("Collection("):len(targetType)-1], true
+		}
 		navigationProp := &models.NavigationProperty{
-			Name:     navProp.Name,
-			Type:     navProp.Type,
-			Partner:  navProp.Partner,
-			Nullable: navProp.Nullable != "false",
+			Name:         navProp.Name,
+			Type:         navProp.Type,
+			Partner:      navProp.Partner,
+			Nullable:     navProp.Nullable != "false",
+			TargetType:   unqualified(targetType),
+			Multiplicity: "0..1",
+			IsCollection: isCollection,
+		}
+		if isCollection {
+			navigationProp.Multiplicity = "*"
+		} else if !navigationProp.Nullable {
+			navigationProp.Multiplicity = "1"
 		}
 		entityType.NavigationProps = append(entityType.NavigationProps, navigationProp)
 	}
@@ -313,16 +516,45 @@ func parseEntitySetV4(es EntitySetV4, namespace string) *models.EntitySet {
 		entityTypeName = parts[len(parts)-1]
 	}
 
-	return &models.EntitySet{
+	entitySet := &models.EntitySet{
 		Name:       es.Name,
 		EntityType: entityTypeName,
-		// OData v4 doesn't have explicit CRUD capability attributes in metadata
-		// We assume all operations are allowed unless restricted by service
-		Creatable:  true,
-		Updatable:  true,
-		Deletable:  true,
-		Searchable: true,
-		Pageable:   true,
+		// All operations are allowed unless Capabilities annotations restrict them
+		Creatable:    true,
+		Updatable:    true,
+		Deletable:    true,
+		Searchable:   true,
+		Pageable:     true,
+		Filterable:   true,
+		Countable:    true,
+		TopSupported: true,
+	}
+
+	// Navigation property bindings name the entity set each navigation property leads to
+	for _, binding := range es.NavigationPropertyBindings {
+		if binding.Path == "" || binding.Target == "" {
+			continue
+		}
+		if entitySet.NavigationTargets == nil {
+			entitySet.NavigationTargets = make(map[string]string)
+		}
+		// Targets in another container are written Container/EntitySet
+		target := binding.Target
+		if i := strings.LastIndex(target, "/"); i >= 0 {
+			target = target[i+1:]
+		}
+		entitySet.NavigationTargets[binding.Path] = target
+	}
+
+	return entitySet
+}
+
+// parseSingletonV4 converts XML singleton to model for OData v4
+func parseSingletonV4(st SingletonV4) *models.Singleton {
+	return &models.Singleton{
+		Name:       st.Name,
+		EntityType: normalizeTypeV4(st.Type),
+		Updatable:  true, // Like entity sets, assume updates are allowed
 	}
 }
 
@@ -337,7 +569,7 @@ func parseFunctionImportV4(fi FunctionImportV4, functions []FunctionV4) *models.
 	}
 	
 	for i := range functions {
-		if functions[i].Name == functionName {
+		if functions[i].Name == functionName && functions[i].IsBound != "true" {
 			function = &functions[i]
 			break
 		}
@@ -385,7 +617,7 @@ func parseActionImportV4(ai ActionImportV4, actions []ActionV4) *models.Function
 	}
 	
 	for i := range actions {
-		if actions[i].Name == actionName {
+		if actions[i].Name == actionName && actions[i].IsBound != "true" {
 			action = &actions[i]
 			break
 		}
@@ -422,6 +654,59 @@ func parseActionImportV4(ai ActionImportV4, actions []ActionV4) *models.Function
 	return actionImport
 }
 
+// parseBoundFunctionV4 converts a bound function to model for OData v4. The
+// first parameter is the binding parameter and is not passed by the caller.
+func parseBoundFunctionV4(fn FunctionV4, namespace string) *models.FunctionImport {
+	if len(fn.Parameters) == 0 {
+		return nil
+	}
+	bound := &models.FunctionImport{
+		Name:          fn.Name,
+		HTTPMethod:    "GET", // Functions are always GET in OData v4
+		Parameters:    parseBoundParametersV4(fn.Parameters[1:]),
+		IsBound:       true,
+		QualifiedName: namespace + "." + fn.Name,
+		BindingType:   normalizeTypeV4(fn.Parameters[0].Type),
+	}
+	if fn.ReturnType.Type != "" {
+		bound.ReturnType = normalizeTypeV4(fn.ReturnType.Type)
+	}
+	return bound
+}
+
+// parseBoundActionV4 converts a bound action to model for OData v4
+func parseBoundActionV4(action ActionV4, namespace string) *models.FunctionImport {
+	if len(action.Parameters) == 0 {
+		return nil
+	}
+	bound := &models.FunctionImport{
+		Name:          action.Name,
+		HTTPMethod:    "POST", // Actions are always POST in OData v4
+		Parameters:    parseBoundParametersV4(action.Parameters[1:]),
+		IsBound:       true,
+		IsAction:      true,
+		QualifiedName: namespace + "." + action.Name,
+		BindingType:   normalizeTypeV4(action.Parameters[0].Type),
+	}
+	if action.ReturnType != nil && action.ReturnType.Type != "" {
+		bound.ReturnType = normalizeTypeV4(action.ReturnType.Type)
+	}
+	return bound
+}
+
+// parseBoundParametersV4 converts the non-binding parameters of a bound operation
+func parseBoundParametersV4(params []ParameterV4) []*models.FunctionParameter {
+	parameters := make([]*models.FunctionParameter, 0, len(params))
+	for _, param := range params {
+		parameters = append(parameters, &models.FunctionParameter{
+			Name:     param.Name,
+			Type:     normalizeTypeV4(param.Type),
+			Nullable: param.Nullable != "false",
+		})
+	}
+	return parameters
+}
+
 // normalizeTypeV4 normalizes OData v4 type names
 func normalizeTypeV4(typeName string) string {
 	// Handle collection types
@@ -0,0 +1,272 @@
+package metadata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+// Reference represents an edmx:Reference to another metadata document
+type Reference struct {
+	XMLName  xml.Name  `xml:"Reference"`
+	URI      string    `xml:"Uri,attr"`
+	Includes []Include `xml:"Include"`
+}
+
+// Include represents a schema namespace included from a referenced document
+type Include struct {
+	XMLName   xml.Name `xml:"Include"`
+	Namespace string   `xml:"Namespace,attr"`
+	Alias     string   `xml:"Alias,attr"`
+}
+
+// typeResolver resolves namespace- or alias-qualified type names against the
+// types of all schemas in a document
+type typeResolver struct {
+	namespaces map[string]map[string]bool // Namespace -> type names
+	aliases    map[string]string          // Alias -> namespace
+	defined    map[string]int             // Type name -> number of namespaces defining it
+}
+
+func newTypeResolver() *typeResolver {
+	return &typeResolver{
+		namespaces: make(map[string]map[string]bool),
+		aliases:    make(map[string]string),
+		defined:    make(map[string]int),
+	}
+}
+
+// addNamespace registers a schema or included namespace and its alias
+func (r *typeResolver) addNamespace(namespace, alias string) {
+	if _, ok := r.namespaces[namespace]; !ok {
+		r.namespaces[namespace] = make(map[string]bool)
+	}
+	if alias != "" {
+		r.aliases[alias] = namespace
+	}
+}
+
+// addType registers a type defined in a namespace. All types must be
+// registered before names are resolved, so that shared names are known.
+func (r *typeResolver) addType(namespace, name string) {
+	r.addNamespace(namespace, "")
+	if !r.namespaces[namespace][name] {
+		r.namespaces[namespace][name] = true
+		r.defined[name]++
+	}
+}
+
+// key returns the map key of a type: its unqualified name, or its qualified
+// name when several namespaces define a type of that name
+func (r *typeResolver) key(namespace, name string) string {
+	if r.defined[name] > 1 {
+		return namespace + "." + name
+	}
+	return name
+}
+
+// resolve returns the map key of a type. A qualified name resolves when its
+// namespace or alias defines the type, an unqualified name when exactly one
+// schema defines it.
+func (r *typeResolver) resolve(typeName string) (string, bool) {
+	i := strings.LastIndex(typeName, ".")
+	if i < 0 {
+		for _, types := range r.namespaces {
+			if types[typeName] && r.defined[typeName] == 1 {
+				return typeName, true
+			}
+		}
+		return typeName, false
+	}
+
+	namespace, name := typeName[:i], typeName[i+1:]
+	if aliased, ok := r.aliases[namespace]; ok {
+		namespace = aliased
+	}
+	if r.namespaces[namespace][name] {
+		return r.key(namespace, name), true
+	}
+	return typeName, false
+}
+
+// propertyType returns the map key of the type of a property when several
+// namespaces define a type of its name, and false for any other type, which
+// keeps its unqualified name
+func (r *typeResolver) propertyType(typeName string) (string, bool) {
+	if item, ok := strings.CutPrefix(typeName, "Collection("); ok && strings.HasSuffix(item, ")") {
+		key, ok := r.propertyType(strings.TrimSuffix(item, ")"))
+		return "Collection(" + key + ")", ok
+	}
+	if r.defined[unqualified(typeName)] < 2 {
+		return typeName, false
+	}
+	return r.resolve(typeName)
+}
+
+// qualifyPropertyTypes points the properties whose type name several
+// namespaces define at the map key of the declared type
+func qualifyPropertyTypes(properties []*models.EntityProperty, declared []PropertyV4, resolver *typeResolver) {
+	for _, prop := range declared {
+		key, ok := resolver.propertyType(prop.Type)
+		if !ok {
+			continue
+		}
+		for _, property := range properties {
+			if property.Name == prop.Name {
+				property.Type = key
+			}
+		}
+	}
+}
+
+// entityTypeName resolves the entity type of an entity set or singleton. An
+// unresolved type keeps its qualified name, so it is not mistaken for a type
+// of the same name in another namespace.
+func (r *typeResolver) entityTypeName(typeName string) string {
+	name, _ := r.resolve(typeName)
+	return name
+}
+
+// parseReferences converts edmx:Reference elements to model and registers the
+// aliases of their includes
+func parseReferences(refs []Reference, resolver *typeResolver) []*models.Reference {
+	references := make([]*models.Reference, 0, len(refs))
+	for _, ref := range refs {
+		reference := &models.Reference{URI: ref.URI}
+		for _, inc := range ref.Includes {
+			reference.Includes = append(reference.Includes, &models.ReferenceInclude{
+				Namespace: inc.Namespace,
+				Alias:     inc.Alias,
+			})
+			resolver.addNamespace(inc.Namespace, inc.Alias)
+		}
+		references = append(references, reference)
+	}
+	return references
+}
+
+// UnresolvedEntitySets returns the entity sets whose entity type is not in the metadata
+func UnresolvedEntitySets(meta *models.ODataMetadata) map[string]string {
+	unresolved := make(map[string]string)
+	for name, entitySet := range meta.EntitySets {
+		if _, ok := meta.EntityTypes[entitySet.EntityType]; !ok {
+			unresolved[name] = entitySet.EntityType
+		}
+	}
+	return unresolved
+}
+
+// MergeReferencedTypes adds the entity, complex and enum types of a referenced
+// metadata document and resolves the entity sets and singletons that use them
+func MergeReferencedTypes(meta *models.ODataMetadata, data []byte) error {
+	resolver := newTypeResolver()
+	for _, ref := range meta.References {
+		for _, inc := range ref.Includes {
+			resolver.addNamespace(inc.Namespace, inc.Alias)
+		}
+	}
+
+	// Types of the service document count as one more namespace, so a
+	// referenced type of the same name gets a qualified key instead of
+	// replacing it
+	countDefined(resolver, meta.EntityTypes)
+	countDefined(resolver, meta.ComplexTypes)
+	countDefined(resolver, meta.EnumTypes)
+
+	if IsODataV4(data) {
+		var edmx EDMXV4
+		if err := xml.Unmarshal(data, &edmx); err != nil {
+			return fmt.Errorf("failed to parse referenced metadata XML: %w", err)
+		}
+		registerTypesV4(edmx.DataServices.Schemas, resolver)
+		for _, schema := range edmx.DataServices.Schemas {
+			for _, et := range schema.EntityTypes {
+				entityType := parseEntityTypeV4(et)
+				qualifyPropertyTypes(entityType.Properties, et.Properties, resolver)
+				meta.EntityTypes[resolver.key(schema.Namespace, et.Name)] = entityType
+			}
+			for _, ct := range schema.ComplexTypes {
+				if meta.ComplexTypes == nil {
+					meta.ComplexTypes = make(map[string]*models.ComplexType)
+				}
+				complexType := parseComplexTypeV4(ct)
+				qualifyPropertyTypes(complexType.Properties, ct.Properties, resolver)
+				meta.ComplexTypes[resolver.key(schema.Namespace, ct.Name)] = complexType
+			}
+			for _, et := range schema.EnumTypes {
+				if meta.EnumTypes == nil {
+					meta.EnumTypes = make(map[string]*models.EnumType)
+				}
+				meta.EnumTypes[resolver.key(schema.Namespace, et.Name)] = parseEnumTypeV4(et)
+			}
+		}
+	} else {
+		var edmx EDMX
+		if err := xml.Unmarshal(data, &edmx); err != nil {
+			return fmt.Errorf("failed to parse referenced metadata XML: %w", err)
+		}
+		for _, schema := range edmx.DataServices.Schemas {
+			resolver.addNamespace(schema.Namespace, schema.Alias)
+			for _, et := range schema.EntityTypes {
+				resolver.addType(schema.Namespace, et.Name)
+			}
+		}
+		for _, schema := range edmx.DataServices.Schemas {
+			for _, et := range schema.EntityTypes {
+				meta.EntityTypes[resolver.key(schema.Namespace, et.Name)] = parseEntityType(et)
+			}
+		}
+	}
+
+	for _, entitySet := range meta.EntitySets {
+		if name, ok := resolver.resolve(entitySet.EntityType); ok {
+			entitySet.EntityType = name
+		}
+	}
+	for _, singleton := range meta.Singletons {
+		if name, ok := resolver.resolve(singleton.EntityType); ok {
+			singleton.EntityType = name
+		}
+	}
+	return nil
+}
+
+// countDefined counts the unqualified names of a type map as defined by one more namespace
+func countDefined[T any](r *typeResolver, types map[string]T) {
+	for name := range types {
+		if !strings.Contains(name, ".") {
+			r.defined[name]++
+		}
+	}
+}
+
+// ReferencesFor returns the URIs of the referenced documents that include the
+// namespace or alias of an unresolved entity type
+func ReferencesFor(meta *models.ODataMetadata, unresolved map[string]string) []string {
+	uris := make([]string, 0)
+	for _, ref := range meta.References {
+		for _, inc := range ref.Includes {
+			if includesType(inc, unresolved) {
+				uris = append(uris, ref.URI)
+				break
+			}
+		}
+	}
+	return uris
+}
+
+// includesType reports whether an include qualifies one of the type names
+func includesType(inc *models.ReferenceInclude, typeNames map[string]string) bool {
+	for _, typeName := range typeNames {
+		i := strings.LastIndex(typeName, ".")
+		if i < 0 {
+			continue
+		}
+		if prefix := typeName[:i]; prefix == inc.Namespace || (inc.Alias != "" && prefix == inc.Alias) {
+			return true
+		}
+	}
+	return false
+}
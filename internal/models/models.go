@@ -9,6 +9,18 @@ type EntityProperty struct {
 	Nullable    bool    `json:"nullable"`
 	IsKey       bool    `json:"is_key"`
 	Description *string `json:"description,omitempty"`
+	MaxLength   int     `json:"max_length,omitempty"` // 0 when unbounded
+	Precision   int     `json:"precision,omitempty"`  // Total digits of Edm.Decimal, 0 when unspecified
+	Scale       int     `json:"scale,omitempty"`      // Digits after the decimal point, -1 when variable
+
+	// SAP property annotations (sap:*), true unless the service restricts the property
+	Filterable       bool   `json:"filterable"`
+	Sortable         bool   `json:"sortable"`
+	Creatable        bool   `json:"creatable"`
+	Updatable        bool   `json:"updatable"`
+	RequiredInFilter bool   `json:"required_in_filter,omitempty"`
+	DisplayFormat    string `json:"display_format,omitempty"` // e.g. "Date", "UpperCase", "NonNegative"
+	Text             string `json:"text,omitempty"`           // Property holding the text of this property's value
 }
 
 // EntityType represents an OData entity type definition
@@ -20,6 +32,26 @@ type EntityType struct {
 	NavigationProps []*NavigationProperty `json:"navigation_properties,omitempty"`
 }
 
+// ComplexType represents an OData v4 complex type, a structured value without a key
+type ComplexType struct {
+	Name       string            `json:"name"`
+	Properties []*EntityProperty `json:"properties"`
+}
+
+// EnumType represents an OData v4 enum type
+type EnumType struct {
+	Name           string        `json:"name"`
+	UnderlyingType string        `json:"underlying_type,omitempty"`
+	IsFlags        bool          `json:"is_flags,omitempty"` // Values combine several members, e.g. "Red,Blue"
+	Members        []*EnumMember `json:"members"`
+}
+
+// EnumMember represents a member of an enum type
+type EnumMember struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
 // NavigationProperty represents a navigation property in an entity type
 type NavigationProperty struct {
 	Name         string `json:"name"`
@@ -29,18 +61,88 @@ type NavigationProperty struct {
 	Type         string `json:"type,omitempty"`         // v4 only
 	Partner      string `json:"partner,omitempty"`      // v4 only
 	Nullable     bool   `json:"nullable"`               // v4 only
+	TargetType   string `json:"target_type,omitempty"`  // Entity type of the target, without namespace
+	TargetSet    string `json:"target_set,omitempty"`   // Entity set of the target, when it is the same for all entity sets of the type
+	Multiplicity string `json:"multiplicity,omitempty"` // "1", "0..1" or "*"
+	IsCollection bool   `json:"is_collection"`          // Leads to many entities (multiplicity "*")
+}
+
+// Association represents an OData v2 relationship between two entity types
+type Association struct {
+	Name string            `json:"name"`
+	Ends []*AssociationEnd `json:"ends"`
+}
+
+// AssociationEnd is one side of an association
+type AssociationEnd struct {
+	Role         string `json:"role"`
+	EntityType   string `json:"entity_type"` // Without namespace
+	Multiplicity string `json:"multiplicity"`
+}
+
+// AssociationSet relates the entity sets of the two ends of an association
+type AssociationSet struct {
+	Name        string               `json:"name"`
+	Association string               `json:"association"` // Without namespace
+	Ends        []*AssociationSetEnd `json:"ends"`
+}
+
+// AssociationSetEnd names the entity set of an association role
+type AssociationSetEnd struct {
+	Role      string `json:"role"`
+	EntitySet string `json:"entity_set"`
+}
+
+// Reference is an edmx:Reference to another metadata document, such as a
+// vocabulary or a schema shared between services
+type Reference struct {
+	URI      string              `json:"uri"`
+	Includes []*ReferenceInclude `json:"includes,omitempty"`
+}
+
+// ReferenceInclude is a schema namespace included from a referenced document
+type ReferenceInclude struct {
+	Namespace string `json:"namespace"`
+	Alias     string `json:"alias,omitempty"`
+}
+
+// Relationship is an edge of the relationship graph: a navigation property
+// of an entity set and the entity set it leads to
+type Relationship struct {
+	EntitySet    string `json:"entity_set"`
+	Navigation   string `json:"navigation"`
+	TargetSet    string `json:"target_set"`
+	TargetType   string `json:"target_type"`
+	Multiplicity string `json:"multiplicity"`
 }
 
 // EntitySet represents an OData entity set
 type EntitySet struct {
-	Name         string  `json:"name"`
-	EntityType   string  `json:"entity_type"`
-	Creatable    bool    `json:"creatable"`
-	Updatable    bool    `json:"updatable"`
-	Deletable    bool    `json:"deletable"`
-	Searchable   bool    `json:"searchable"`
-	Pageable     bool    `json:"pageable"`
-	Description  *string `json:"description,omitempty"`
+	Name              string            `json:"name"`
+	EntityType        string            `json:"entity_type"`
+	Creatable         bool              `json:"creatable"`
+	Updatable         bool              `json:"updatable"`
+	Deletable         bool              `json:"deletable"`
+	Searchable        bool              `json:"searchable"`
+	Pageable          bool              `json:"pageable"`
+	Description       *string           `json:"description,omitempty"`
+	NavigationTargets map[string]string `json:"navigation_targets,omitempty"` // Navigation property -> target entity set
+
+	// OData v4 Capabilities restrictions, unrestricted unless annotated
+	Filterable               bool     `json:"filterable"`
+	Countable                bool     `json:"countable"`
+	TopSupported             bool     `json:"top_supported"`
+	RequiresFilter           bool     `json:"requires_filter,omitempty"`
+	RequiredFilterProperties []string `json:"required_filter_properties,omitempty"`
+	NonFilterableProperties  []string `json:"non_filterable_properties,omitempty"`
+}
+
+// Singleton represents an OData v4 singleton, a single entity addressed by name
+type Singleton struct {
+	Name        string  `json:"name"`
+	EntityType  string  `json:"entity_type"`
+	Updatable   bool    `json:"updatable"`
+	Description *string `json:"description,omitempty"`
 }
 
 // FunctionImportParameter represents a parameter for a function import
@@ -60,6 +162,8 @@ type FunctionImport struct {
 	Description *string                    `json:"description,omitempty"`
 	IsBound     bool                       `json:"is_bound,omitempty"`     // v4 only
 	IsAction    bool                       `json:"is_action,omitempty"`    // v4 only (true for actions, false for functions)
+	QualifiedName string                   `json:"qualified_name,omitempty"` // v4 bound only: Namespace.Name used in the URL
+	BindingType   string                   `json:"binding_type,omitempty"`   // v4 bound only: entity type or Collection(entity type)
 }
 
 // FunctionParameter represents a parameter for a function/action
@@ -76,6 +180,13 @@ type ODataMetadata struct {
 	EntityTypes    map[string]*EntityType   `json:"entity_types"`
 	EntitySets     map[string]*EntitySet    `json:"entity_sets"`
 	FunctionImports map[string]*FunctionImport `json:"function_imports"`
+	Singletons      map[string]*Singleton      `json:"singletons,omitempty"`    // v4 only
+	BoundOperations []*FunctionImport          `json:"bound_operations,omitempty"` // v4 only
+	Associations    map[string]*Association    `json:"associations,omitempty"`     // v2 only
+	AssociationSets map[string]*AssociationSet `json:"association_sets,omitempty"` // v2 only
+	References      []*Reference               `json:"references,omitempty"`       // edmx:Reference documents
+	ComplexTypes    map[string]*ComplexType    `json:"complex_types,omitempty"` // v4 only
+	EnumTypes       map[string]*EnumType       `json:"enum_types,omitempty"`    // v4 only
 	SchemaNamespace string                   `json:"schema_namespace"`
 	ContainerName   string                   `json:"container_name"`
 	Version        string                   `json:"version"`
@@ -113,6 +224,25 @@ type ODataResponse struct {
 	Pagination *PaginationInfo   `json:"pagination,omitempty"`
 }
 
+// BatchOperation represents a single request inside an OData $batch
+type BatchOperation struct {
+	Method string                 `json:"method"`          // GET, POST, PUT, PATCH, MERGE or DELETE
+	Path   string                 `json:"path"`            // Entity set name or $<n> reference to an earlier write
+	Key    map[string]interface{} `json:"key,omitempty"`   // Key predicate values, omitted for collection requests
+	Query  map[string]string      `json:"query,omitempty"` // Query options for GET requests
+	Body   map[string]interface{} `json:"body,omitempty"`  // Entity payload for POST/PUT/PATCH/MERGE
+}
+
+// BatchResult represents the outcome of a single operation in a $batch response
+type BatchResult struct {
+	Index  int         `json:"index"`
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Value  interface{} `json:"value,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
 // PaginationInfo provides pagination details like Python implementation
 type PaginationInfo struct {
 	TotalCount        *int64  `json:"total_count,omitempty"`
@@ -145,6 +275,7 @@ type ToolParameter struct {
 
 // TraceInfo represents comprehensive information for trace mode
 type TraceInfo struct {
+	ServiceName      string              `json:"service_name,omitempty"`
 	ServiceURL       string              `json:"service_url"`
 	MCPName          string              `json:"mcp_name"`
 	ToolNaming       string              `json:"tool_naming"`
@@ -154,15 +285,45 @@ type TraceInfo struct {
 	SortTools        bool                `json:"sort_tools"`
 	EntityFilter     []string            `json:"entity_filter,omitempty"`
 	FunctionFilter   []string            `json:"function_filter,omitempty"`
+	ReadOnly         bool                `json:"read_only,omitempty"`
+	NoPostFunctions  bool                `json:"no_post_functions,omitempty"`
+	OperationRules   []string            `json:"operation_rules,omitempty"`
+	DryRun           bool                `json:"dry_run,omitempty"`
 	Authentication   string              `json:"authentication"`
+	TLS              *TLSInfo            `json:"tls,omitempty"`
+	Proxy            string              `json:"proxy,omitempty"`
+	ExtraHeaders     []string            `json:"extra_headers,omitempty"`
+	UnresolvedSets   []string            `json:"unresolved_entity_sets,omitempty"` // Entity sets without a known entity type
 	MetadataSummary  MetadataSummary     `json:"metadata_summary"`
 	RegisteredTools  []ToolInfo          `json:"registered_tools"`
 	TotalTools       int                 `json:"total_tools"`
 }
 
+// TLSInfo describes the TLS settings used to reach the service
+type TLSInfo struct {
+	CAFile             string `json:"ca_file,omitempty"`
+	ClientCertificate  string `json:"client_certificate,omitempty"`
+	ClientCertExpires  string `json:"client_certificate_expires,omitempty"`
+	MinVersion         string `json:"min_version"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// ReloadSummary describes the changes of a metadata reload
+type ReloadSummary struct {
+	AddedEntitySets        []string `json:"added_entity_sets,omitempty"`
+	RemovedEntitySets      []string `json:"removed_entity_sets,omitempty"`
+	AddedFunctionImports   []string `json:"added_function_imports,omitempty"`
+	RemovedFunctionImports []string `json:"removed_function_imports,omitempty"`
+	AddedTools             []string `json:"added_tools,omitempty"`
+	RemovedTools           []string `json:"removed_tools,omitempty"`
+	ChangedTools           []string `json:"changed_tools,omitempty"`
+	TotalTools             int      `json:"total_tools"`
+}
+
 // MetadataSummary represents a summary of parsed metadata
 type MetadataSummary struct {
 	EntityTypes      int `json:"entity_types"`
 	EntitySets       int `json:"entity_sets"`
 	FunctionImports  int `json:"function_imports"`
+	Singletons       int `json:"singletons,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+)
+
+// TestArgumentValidation tests that invalid arguments are rejected before the service is called
+func TestArgumentValidation(t *testing.T) {
+	server, requests := newTestBridge(t, facetsMetadata, &config.Config{ValidateArgs: true})
+
+	resp := invokeTool(t, server, "Materials_create", map[string]interface{}{
+		"Description": strings.Repeat("x", 41),
+		"Price":       "12.345",
+		"Level":       300,
+		"Stock":       "many",
+		"Colour":      "red",
+	})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+
+	var data struct {
+		Tool   string              `json:"tool"`
+		Errors []mcp.ArgumentError `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Error.Data, &data))
+	assert.Equal(t, "Materials_create", data.Tool)
+
+	fields := make(map[string]string)
+	for _, argErr := range data.Errors {
+		fields[argErr.Field] = argErr.Message
+	}
+	assert.Len(t, fields, 5)
+	assert.Contains(t, fields["Description"], "maximum is 40")
+	assert.Contains(t, fields["Price"], "pattern")
+	assert.Contains(t, fields["Level"], "maximum is 255")
+	assert.Contains(t, fields["Stock"], "expected integer")
+	assert.Contains(t, fields["Colour"], "not a known property")
+	assert.Contains(t, resp.Error.Message, "Colour is not a known property")
+	assert.Empty(t, requests())
+
+	// Valid arguments pass, including null for nullable properties
+	resp = invokeTool(t, server, "Materials_create", map[string]interface{}{
+		"Price":       12.5,
+		"Level":       3,
+		"Description": nil,
+	})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, requests(), "POST /Materials")
+
+	// Missing required properties are reported
+	resp = invokeTool(t, server, "Materials_get", map[string]interface{}{})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "MaterialID is required")
+}
+
+// TestArgumentValidationDisabled tests that arguments pass unchecked by default
+func TestArgumentValidationDisabled(t *testing.T) {
+	server, requests := newTestBridge(t, facetsMetadata, &config.Config{})
+
+	resp := invokeTool(t, server, "Materials_create", map[string]interface{}{"Price": "1", "Level": 1, "Colour": "red"})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, requests(), "POST /Materials")
+}
+
+// TestValidateNestedArguments tests validation of arrays of objects such as batch operations
+func TestValidateNestedArguments(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"operations": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"operation": map[string]interface{}{"type": "string", "enum": []string{"get", "filter"}},
+						"key":       map[string]interface{}{"type": "object"},
+					},
+					"required": []string{"operation"},
+				},
+			},
+		},
+	}
+
+	errs := mcp.ValidateArguments(schema, map[string]interface{}{
+		"operations": []interface{}{
+			map[string]interface{}{"operation": "get", "key": map[string]interface{}{"ID": "1"}},
+			map[string]interface{}{"operation": "delete"},
+			map[string]interface{}{"key": "1"},
+		},
+	})
+	assert.Equal(t, []mcp.ArgumentError{
+		{Field: "operations[1].operation", Message: "must be one of [get filter]"},
+		{Field: "operations[2].operation", Message: "is required"},
+		{Field: "operations[2].key", Message: "expected object, got string"},
+	}, errs)
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+// TestParseAssociations tests that v2 associations resolve navigation targets and multiplicities
+func TestParseAssociations(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(navigationV2Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	assoc := meta.Associations["OrderItems"]
+	require.NotNil(t, assoc)
+	require.Len(t, assoc.Ends, 2)
+	assert.Equal(t, "Item", assoc.Ends[1].EntityType)
+	assert.Equal(t, "*", assoc.Ends[1].Multiplicity)
+
+	assocSet := meta.AssociationSets["OrderCustomerSet"]
+	require.NotNil(t, assocSet)
+	assert.Equal(t, "OrderCustomer", assocSet.Association)
+
+	items := meta.EntityTypes["Order"].NavigationProps[0]
+	assert.Equal(t, "*", items.Multiplicity)
+	assert.Equal(t, "OrderItems", items.TargetSet)
+	customer := meta.EntityTypes["Order"].NavigationProps[1]
+	assert.Equal(t, "1", customer.Multiplicity)
+	assert.Equal(t, "Customer", customer.TargetType)
+	assert.Equal(t, "Customers", customer.TargetSet)
+}
+
+// TestRelationshipGraph tests the relationship graph in the service info
+func TestRelationshipGraph(t *testing.T) {
+	server, _ := newTestBridge(t, navigationV2Metadata, &config.Config{})
+
+	info := toolText(t, server, "odata_service_info", map[string]interface{}{"include_metadata": true})
+	assert.Contains(t, info, "associations_detail")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"entity_set": "Orders", "navigation": "Items", "target_set": "OrderItems", "target_type": "Item", "multiplicity": "*"},
+		map[string]interface{}{"entity_set": "Orders", "navigation": "Customer", "target_set": "Customers", "target_type": "Customer", "multiplicity": "1"},
+	}, info["relationships"])
+}
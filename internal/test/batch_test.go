@@ -0,0 +1,322 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/models"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// TestBatchMultipartV2 tests that v2 batches are sent as multipart/mixed with a single changeset
+func TestBatchMultipartV2(t *testing.T) {
+	var requestLines []string
+	var changesets int
+	var batchCalls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(constants.CSRFTokenHeader) == constants.CSRFTokenFetch {
+			w.Header().Set(constants.CSRFTokenHeader, "batch-token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		require.Equal(t, "/$batch", r.URL.Path)
+		assert.Equal(t, "batch-token", r.Header.Get(constants.CSRFTokenHeader))
+		batchCalls++
+
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		require.Equal(t, "multipart/mixed", mediaType)
+
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			partType, partParams, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			if partType == "multipart/mixed" {
+				changesets++
+				csReader := multipart.NewReader(part, partParams["boundary"])
+				for {
+					cp, err := csReader.NextPart()
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+					assert.NotEmpty(t, cp.Header.Get("Content-ID"))
+					requestLines = append(requestLines, readBatchRequestLine(t, cp))
+				}
+				continue
+			}
+
+			requestLines = append(requestLines, readBatchRequestLine(t, part))
+		}
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary=batchresp")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, "--batchresp\r\n"+
+			"Content-Type: multipart/mixed; boundary=csresp\r\n\r\n"+
+			"--csresp\r\n"+
+			"Content-Type: application/http\r\n\r\n"+
+			"HTTP/1.1 201 Created\r\nContent-Type: application/json\r\n\r\n"+
+			`{"d":{"OrderID":"4711","__metadata":{"type":"SRV.Order"}}}`+"\r\n"+
+			"--csresp\r\n"+
+			"Content-Type: application/http\r\n\r\n"+
+			"HTTP/1.1 201 Created\r\nContent-Type: application/json\r\n\r\n"+
+			`{"d":{"OrderID":"4711","ItemNo":"10"}}`+"\r\n"+
+			"--csresp--\r\n"+
+			"--batchresp\r\n"+
+			"Content-Type: application/http\r\n\r\n"+
+			"HTTP/1.1 404 Not Found\r\nContent-Type: application/json\r\n\r\n"+
+			`{"error":{"code":"NOT_FOUND","message":{"lang":"en","value":"Product not found"}}}`+"\r\n"+
+			"--batchresp--\r\n")
+	}))
+	defer server.Close()
+
+	odataClient := client.NewODataClient(server.URL, false)
+
+	ops := []*models.BatchOperation{
+		{Method: "POST", Path: "Orders", Body: map[string]interface{}{"CustomerID": "ALFKI"}},
+		{Method: "POST", Path: "$1/Items", Body: map[string]interface{}{"ProductID": "11"}},
+		{Method: "GET", Path: "Products", Key: map[string]interface{}{"ProductID": 999}},
+	}
+
+	results, err := odataClient.ExecuteBatch(context.Background(), ops, true)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, 1, batchCalls, "All operations should be sent in one request")
+	assert.Equal(t, 1, changesets, "Atomic writes should share one changeset")
+	assert.Equal(t, []string{"POST Orders", "POST $1/Items", "GET Products(999)"}, requestLines)
+
+	assert.Equal(t, 201, results[0].Status)
+	assert.Equal(t, "4711", results[0].Value.(map[string]interface{})["OrderID"])
+	assert.Equal(t, 201, results[1].Status)
+	assert.Equal(t, 404, results[2].Status)
+	assert.Contains(t, results[2].Error, "Product not found")
+}
+
+// TestBatchNonAtomicV2 tests that non-atomic batches put every write in its own changeset
+func TestBatchNonAtomicV2(t *testing.T) {
+	var changesets int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		var parts []string
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Type"), "multipart/mixed") {
+				changesets++
+			}
+			parts = append(parts, "--batchresp\r\n"+
+				"Content-Type: application/http\r\n\r\n"+
+				"HTTP/1.1 204 No Content\r\n\r\n")
+		}
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary=batchresp")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, strings.Join(parts, "\r\n")+"\r\n--batchresp--\r\n")
+	}))
+	defer server.Close()
+
+	odataClient := client.NewODataClient(server.URL, false)
+
+	ops := []*models.BatchOperation{
+		{Method: "DELETE", Path: "Orders", Key: map[string]interface{}{"OrderID": "1"}},
+		{Method: "DELETE", Path: "Orders", Key: map[string]interface{}{"OrderID": "2"}},
+	}
+
+	results, err := odataClient.ExecuteBatch(context.Background(), ops, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, changesets)
+	for _, result := range results {
+		assert.Equal(t, 204, result.Status)
+		assert.Empty(t, result.Error)
+	}
+}
+
+// batchV4Metadata is a minimal v4 service for JSON batch tests
+const batchV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Demo" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Product"><Key><PropertyRef Name="ID"/></Key><Property Name="ID" Type="Edm.Int32" Nullable="false"/></EntityType>
+      <EntityContainer Name="Container"><EntitySet Name="Products" EntityType="Demo.Product"/></EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestBatchNonAtomicReferences tests that Content-ID references are rejected
+// when every write gets its own changeset, where they cannot resolve
+func TestBatchNonAtomicReferences(t *testing.T) {
+	server, requests := newTestBridge(t, navigationV2Metadata, &config.Config{})
+	operations := []interface{}{
+		map[string]interface{}{"operation": "create", "entity_set": "Orders", "data": map[string]interface{}{"ID": "1"}},
+		map[string]interface{}{"operation": "create", "entity_set": "$1/Items", "data": map[string]interface{}{"ID": "1", "Pos": 1}},
+	}
+
+	resp := invokeTool(t, server, "odata_batch", map[string]interface{}{"operations": operations, "atomic": false})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "require atomic=true")
+	assert.Empty(t, requests())
+
+	invokeTool(t, server, "odata_batch", map[string]interface{}{"operations": operations})
+	assert.Contains(t, requests(), "POST /$batch")
+}
+
+// batchV4Metadata is that v4 batches use the JSON batch format with atomicity groups
+func TestBatchJSONV4(t *testing.T) {
+	var received struct {
+		Requests []struct {
+			ID             string                 `json:"id"`
+			Method         string                 `json:"method"`
+			URL            string                 `json:"url"`
+			AtomicityGroup string                 `json:"atomicityGroup"`
+			DependsOn      []string               `json:"dependsOn"`
+			Body           map[string]interface{} `json:"body"`
+		} `json:"requests"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "$metadata"):
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprint(w, batchV4Metadata)
+		case strings.HasSuffix(r.URL.Path, "$batch"):
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"responses": []map[string]interface{}{
+					{"id": "1", "status": 200, "body": map[string]interface{}{"value": []interface{}{map[string]interface{}{"ID": 1}}}},
+					{"id": "2", "status": 201, "body": map[string]interface{}{"ID": 2}},
+					{"id": "3", "status": 204},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	odataClient := client.NewODataClient(server.URL, false)
+	_, err := odataClient.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	ops := []*models.BatchOperation{
+		{Method: "GET", Path: "Products", Query: map[string]string{"$top": "1"}},
+		{Method: "POST", Path: "Products", Body: map[string]interface{}{"ID": 2}},
+		{Method: "DELETE", Path: "Products", Key: map[string]interface{}{"ID": 3}},
+	}
+
+	results, err := odataClient.ExecuteBatch(context.Background(), ops, true)
+	require.NoError(t, err)
+
+	require.Len(t, received.Requests, 3)
+	assert.Equal(t, "Products?%24top=1", received.Requests[0].URL)
+	assert.Empty(t, received.Requests[0].AtomicityGroup)
+	assert.NotEmpty(t, received.Requests[1].AtomicityGroup)
+	assert.Equal(t, received.Requests[1].AtomicityGroup, received.Requests[2].AtomicityGroup)
+	assert.Equal(t, "Products(3)", received.Requests[2].URL)
+	for _, request := range received.Requests {
+		assert.Empty(t, request.DependsOn, request.ID)
+	}
+
+	assert.Equal(t, 200, results[0].Status)
+	assert.Len(t, results[0].Value, 1)
+	assert.Equal(t, 201, results[1].Status)
+	assert.Equal(t, 204, results[2].Status)
+}
+
+// TestBatchJSONV4DependsOn tests that v4 requests addressing an earlier request declare it in dependsOn
+func TestBatchJSONV4DependsOn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, batchV4Metadata)
+	}))
+	defer server.Close()
+
+	odataClient := client.NewODataClient(server.URL, false)
+	_, err := odataClient.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	ops := []*models.BatchOperation{
+		{Method: "POST", Path: "Products", Body: map[string]interface{}{"ID": 1}},
+		{Method: "POST", Path: "$1/Parts", Body: map[string]interface{}{"ID": 2}},
+	}
+	ctx, recorder := client.WithDryRun(context.Background())
+	_, err = odataClient.ExecuteBatch(ctx, ops, true)
+	require.NoError(t, err)
+
+	requests := recorder.Requests()
+	require.Len(t, requests, 1)
+	sent := requests[0].Body.(map[string]interface{})["requests"].([]interface{})
+	require.Len(t, sent, 2)
+	assert.NotContains(t, sent[0], "dependsOn")
+	assert.Equal(t, "$1/Parts", sent[1].(map[string]interface{})["url"])
+	assert.Equal(t, []interface{}{"1"}, sent[1].(map[string]interface{})["dependsOn"])
+}
+
+// readBatchRequestLine returns the method and URL of an application/http batch part
+func readBatchRequestLine(t *testing.T, part io.Reader) string {
+	line, err := bufio.NewReader(part).ReadString('\n')
+	require.NoError(t, err)
+	fields := strings.Fields(line)
+	require.Len(t, fields, 3)
+	return fields[0] + " " + fields[1]
+}
+
+// TestBatchUpdateMethod tests that batch updates only accept PUT, PATCH and MERGE
+func TestBatchUpdateMethod(t *testing.T) {
+	server, requests := newTestBridge(t, overridesMetadata, &config.Config{
+		OperationRules: []config.OperationRule{{Entity: "Products", Deny: []string{"delete"}}},
+	})
+
+	update := func(method string) *transport.Message {
+		return invokeTool(t, server, "odata_batch", map[string]interface{}{"operations": []interface{}{
+			map[string]interface{}{
+				"operation":  "update",
+				"entity_set": "Products",
+				"method":     method,
+				"key":        map[string]interface{}{"ID": "1"},
+				"data":       map[string]interface{}{"Name": "Bolt"},
+			},
+		}})
+	}
+
+	resp := update("DELETE")
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "unsupported update method")
+	assert.Empty(t, requests())
+
+	// The mock service does not answer batches, only the request matters here
+	update("patch")
+	assert.Contains(t, requests(), "POST /$batch")
+}
@@ -0,0 +1,180 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+)
+
+const overridesMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" Version="1.0">
+  <edmx:DataServices>
+    <Schema xmlns="http://schemas.microsoft.com/ado/2008/09/edm" Namespace="TestNamespace">
+      <EntityType Name="Product">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Name" Type="Edm.String"/>
+        <Property Name="Cost" Type="Edm.Decimal"/>
+      </EntityType>
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="TestContainer">
+        <EntitySet Name="Products" EntityType="TestNamespace.Product"/>
+        <EntitySet Name="Orders" EntityType="TestNamespace.Order"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// writeConfigFile writes a configuration file with the given name and content
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// TestConfigFileFormats tests that YAML, JSON and TOML files fill the same options
+func TestConfigFileFormats(t *testing.T) {
+	files := map[string]string{
+		"bridge.yaml": `
+service_url: https://erp.example.com/odata/
+username: alice
+tool_postfix: erp
+max_items: 25
+headers:
+  - "SAP-Client: 100"
+entity_overrides:
+  Orders:
+    read_only: true
+    hidden_fields: [Cost]
+    description: Sales orders of the current company
+`,
+		"bridge.json": `{
+  "service_url": "https://erp.example.com/odata/",
+  "username": "alice",
+  "tool_postfix": "erp",
+  "max_items": 25,
+  "headers": ["SAP-Client: 100"],
+  "entity_overrides": {
+    "Orders": {"read_only": true, "hidden_fields": ["Cost"], "description": "Sales orders of the current company"}
+  }
+}`,
+		"bridge.toml": `
+service_url = "https://erp.example.com/odata/"
+username = "alice"
+tool_postfix = "erp"
+max_items = 25
+headers = ["SAP-Client: 100"]
+
+[entity_overrides.Orders]
+read_only = true
+hidden_fields = ["Cost"]
+description = "Sales orders of the current company"
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Config{MaxResponseSize: 1024, MaxItems: 100}
+			require.NoError(t, cfg.LoadFile(writeConfigFile(t, name, content)))
+
+			assert.Equal(t, "https://erp.example.com/odata/", cfg.ServiceURL)
+			assert.Equal(t, "alice", cfg.Username)
+			assert.Equal(t, "erp", cfg.ToolPostfix)
+			assert.Equal(t, 25, cfg.MaxItems)
+			assert.Equal(t, 1024, cfg.MaxResponseSize, "options missing from the file are kept")
+			assert.Equal(t, []string{"SAP-Client: 100"}, cfg.Headers)
+
+			override, ok := cfg.EntityOverride("Orders")
+			require.True(t, ok)
+			assert.True(t, override.ReadOnly)
+			assert.True(t, override.IsHidden("Cost"))
+			assert.Equal(t, "Sales orders of the current company", override.Description)
+		})
+	}
+
+	cfg := &config.Config{}
+	assert.Error(t, cfg.LoadFile(writeConfigFile(t, "bridge.yaml", "max_items: [")))
+	assert.Error(t, cfg.LoadFile(filepath.Join(t.TempDir(), "missing.yaml")))
+}
+
+// TestEntityOverrides tests read-only entity sets, hidden fields and custom descriptions
+func TestEntityOverrides(t *testing.T) {
+	posted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/$metadata"):
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(overridesMetadata))
+		case r.Method == http.MethodHead || r.Header.Get("X-CSRF-Token") == "Fetch":
+			w.Header().Set("X-CSRF-Token", "token")
+		case r.Method == http.MethodPost:
+			posted = true
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"d":{"ID":"1","Name":"Bolt","Cost":"0.10"}}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"d":{"results":[{"ID":"1","Name":"Bolt","Cost":"0.10"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	require.NoError(t, cfg.LoadFile(writeConfigFile(t, "bridge.yaml", `
+service_url: `+server.URL+`/
+no_postfix: true
+entity_overrides:
+  orders:
+    read_only: true
+  Products:
+    hidden_fields: [cost]
+    description: Catalog of purchasable parts
+    field_descriptions:
+      Name: Display name shown to customers
+`)))
+
+	odataBridge, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+
+	tools := make(map[string]*mcp.Tool)
+	for _, tool := range odataBridge.GetServer().GetTools() {
+		tools[tool.Name] = tool
+	}
+
+	// Read-only entity sets keep only the read tools
+	assert.Contains(t, tools, "Orders_filter")
+	assert.Contains(t, tools, "Orders_get")
+	assert.NotContains(t, tools, "Orders_create")
+	assert.NotContains(t, tools, "Orders_update")
+	assert.NotContains(t, tools, "Orders_delete")
+
+	// Hidden fields are not part of write schemas, custom descriptions are applied
+	create := tools["Products_create"]
+	require.NotNil(t, create)
+	assert.Contains(t, create.Description, "Catalog of purchasable parts")
+	properties := create.InputSchema["properties"].(map[string]interface{})
+	assert.NotContains(t, properties, "Cost")
+	assert.Equal(t, "Display name shown to customers", properties["Name"].(map[string]interface{})["description"])
+
+	// Hidden fields are matched case-insensitively, removed from responses and rejected in payloads
+	result := callTool(t, odataBridge.GetServer(), "Products_filter")
+	text := result["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+	assert.Contains(t, text, "Bolt")
+	assert.NotContains(t, text, "Cost")
+
+	resp := invokeTool(t, odataBridge.GetServer(), "Products_create", map[string]interface{}{"Name": "Nut", "Cost": 1})
+	require.NotNil(t, resp.Error)
+	assert.False(t, posted)
+}
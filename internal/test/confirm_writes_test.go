@@ -0,0 +1,179 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+)
+
+// newConfirmingBridge starts a mock service and a bridge with --confirm-writes,
+// returning the MCP server and the write requests that reached the service
+func newConfirmingBridge(t *testing.T, metadata string, ttl time.Duration) (*mcp.Server, func() []string) {
+	var (
+		mu     sync.Mutex
+		writes []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/$metadata"):
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(metadata))
+			return
+		case r.Header.Get("X-CSRF-Token") == "Fetch":
+			w.Header().Set("X-CSRF-Token", "token")
+			return
+		case r.Method != http.MethodGet:
+			mu.Lock()
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"__metadata":{"uri":"Products('1')"},"ID":"1","Name":"Bolt","Cost":"0.10"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{
+		ServiceURL:    server.URL + "/",
+		NoPostfix:     true,
+		ConfirmWrites: true,
+		ConfirmTTL:    ttl,
+	})
+	require.NoError(t, err)
+
+	return odataBridge.GetServer(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), writes...)
+	}
+}
+
+// toolText returns the text content of a successful tool call
+func toolText(t *testing.T, server *mcp.Server, name string, args map[string]interface{}) map[string]interface{} {
+	resp := invokeTool(t, server, name, args)
+	require.Nil(t, resp.Error, "tool %s failed", name)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	text := result["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &payload))
+	return payload
+}
+
+// TestConfirmDelete tests the preview, confirmation and single use of delete tokens
+func TestConfirmDelete(t *testing.T) {
+	server, writes := newConfirmingBridge(t, overridesMetadata, time.Minute)
+
+	preview := toolText(t, server, "Products_delete", map[string]interface{}{"ID": "1"})
+	assert.Equal(t, true, preview["confirmation_required"])
+	assert.Equal(t, "DELETE", preview["method"])
+	assert.True(t, strings.HasSuffix(preview["url"].(string), "/Products('1')"))
+	assert.Equal(t, "Bolt", preview["current"].(map[string]interface{})["Name"])
+	assert.NotContains(t, preview["current"], "__metadata")
+	assert.Empty(t, writes(), "the preview must not write")
+
+	// A token only authorizes the previewed arguments and is used up by a mismatch
+	token := preview["confirmation_token"].(string)
+	resp := invokeTool(t, server, "Products_delete", map[string]interface{}{"ID": "2", "_confirmation_token": token})
+	require.NotNil(t, resp.Error)
+	resp = invokeTool(t, server, "Products_delete", map[string]interface{}{"ID": "1", "_confirmation_token": token})
+	require.NotNil(t, resp.Error)
+	assert.Empty(t, writes())
+
+	preview = toolText(t, server, "Products_delete", map[string]interface{}{"ID": "1"})
+	token = preview["confirmation_token"].(string)
+	resp = invokeTool(t, server, "Products_delete", map[string]interface{}{"ID": "1", "_confirmation_token": token})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, []string{"DELETE /Products('1')"}, writes())
+
+	// Tokens are single use
+	resp = invokeTool(t, server, "Products_delete", map[string]interface{}{"ID": "1", "_confirmation_token": token})
+	require.NotNil(t, resp.Error)
+	assert.Len(t, writes(), 1)
+}
+
+// TestConfirmUpdateDiff tests that update previews show the changes against the current entity
+func TestConfirmUpdateDiff(t *testing.T) {
+	server, writes := newConfirmingBridge(t, overridesMetadata, time.Minute)
+
+	args := map[string]interface{}{"ID": "1", "Name": "Hex bolt", "Cost": 0.10, "_method": "MERGE"}
+	preview := toolText(t, server, "Products_update", args)
+	assert.Equal(t, "MERGE", preview["method"])
+	assert.Equal(t, map[string]interface{}{
+		"Name": map[string]interface{}{"current": "Bolt", "new": "Hex bolt"},
+	}, preview["changes"])
+	assert.Equal(t, []interface{}{"Cost"}, preview["unchanged"])
+	assert.Empty(t, writes())
+
+	args["_confirmation_token"] = preview["confirmation_token"]
+	resp := invokeTool(t, server, "Products_update", args)
+	require.Nil(t, resp.Error)
+	assert.Equal(t, []string{"MERGE /Products('1')"}, writes())
+}
+
+// TestConfirmTokenExpiry tests that expired tokens are rejected
+func TestConfirmTokenExpiry(t *testing.T) {
+	server, writes := newConfirmingBridge(t, overridesMetadata, 10*time.Millisecond)
+
+	preview := toolText(t, server, "Products_delete", map[string]interface{}{"ID": "1"})
+	time.Sleep(20 * time.Millisecond)
+
+	resp := invokeTool(t, server, "Products_delete", map[string]interface{}{"ID": "1", "_confirmation_token": preview["confirmation_token"]})
+	require.NotNil(t, resp.Error)
+	assert.Empty(t, writes())
+}
+
+// TestConfirmPostFunction tests that only POST function imports need confirmation
+func TestConfirmPostFunction(t *testing.T) {
+	server, writes := newConfirmingBridge(t, writeProtectionMetadata, time.Minute)
+
+	// GET functions run directly
+	resp := invokeTool(t, server, "GetStatus", nil)
+	require.Nil(t, resp.Error)
+	assert.Empty(t, writes())
+
+	preview := toolText(t, server, "ReleaseOrder", nil)
+	assert.Equal(t, "POST", preview["method"])
+	assert.True(t, strings.HasSuffix(preview["url"].(string), "/ReleaseOrder"))
+	assert.Empty(t, writes())
+
+	resp = invokeTool(t, server, "ReleaseOrder", map[string]interface{}{"_confirmation_token": preview["confirmation_token"]})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, []string{"POST /ReleaseOrder"}, writes())
+}
+
+// TestConfirmBatch tests that batches need confirmation only when they update or delete
+func TestConfirmBatch(t *testing.T) {
+	server, writes := newConfirmingBridge(t, overridesMetadata, time.Minute)
+
+	read := map[string]interface{}{"operations": []interface{}{
+		map[string]interface{}{"operation": "get", "entity_set": "Products", "key": map[string]interface{}{"ID": "1"}},
+	}}
+	invokeTool(t, server, "odata_batch", read)
+	require.Equal(t, []string{"POST /$batch"}, writes(), "read-only batches are sent without confirmation")
+
+	remove := map[string]interface{}{"operations": []interface{}{
+		map[string]interface{}{"operation": "delete", "entity_set": "Products", "key": map[string]interface{}{"ID": "1"}},
+	}}
+	preview := toolText(t, server, "odata_batch", remove)
+	assert.Equal(t, true, preview["confirmation_required"])
+	operations := preview["operations"].([]interface{})
+	require.Len(t, operations, 1)
+	assert.Equal(t, "DELETE", operations[0].(map[string]interface{})["method"])
+	assert.Len(t, writes(), 1)
+}
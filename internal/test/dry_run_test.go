@@ -0,0 +1,142 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+)
+
+// newTestBridge starts a mock service and a bridge with basic authentication,
+// returning the MCP server and the non-metadata requests that reached the service
+func newTestBridge(t *testing.T, metadata string, cfg *config.Config) (*mcp.Server, func() []string) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(metadata))
+			return
+		}
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"ID":"1","Name":"Bolt","Cost":"0.10"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg.ServiceURL = server.URL + "/"
+	cfg.Username = "alice"
+	cfg.Password = "secret"
+	cfg.NoPostfix = true
+	odataBridge, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+
+	return odataBridge.GetServer(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+// dryRunRequest returns the single request of a dry run result
+func dryRunRequest(t *testing.T, result map[string]interface{}) map[string]interface{} {
+	assert.Equal(t, true, result["dry_run"])
+	requests := result["requests"].([]interface{})
+	require.Len(t, requests, 1)
+	return requests[0].(map[string]interface{})
+}
+
+// TestDryRunArgument tests that $dry_run returns the converted payload and redacted headers
+func TestDryRunArgument(t *testing.T) {
+	server, requests := newTestBridge(t, overridesMetadata, &config.Config{
+		LegacyDates:  true,
+		ExtraHeaders: map[string]string{"X-Api-Key": "k3y", "X-Route": "east"},
+	})
+
+	result := toolText(t, server, "Products_create", map[string]interface{}{
+		"Name":     "Bolt",
+		"Cost":     12.5,
+		"$dry_run": true,
+	})
+	request := dryRunRequest(t, result)
+	assert.Equal(t, "POST", request["method"])
+	assert.True(t, strings.HasSuffix(request["url"].(string), "/Products"))
+
+	// Numbers are sent as strings for SAP and the dry run flag is not part of the payload
+	assert.Equal(t, map[string]interface{}{"Name": "Bolt", "Cost": "12.5"}, request["body"])
+
+	headers := request["headers"].(map[string]interface{})
+	assert.Equal(t, "Basic [REDACTED]", headers["Authorization"])
+	assert.Equal(t, "[REDACTED]", headers["X-Api-Key"])
+	assert.Equal(t, "east", headers["X-Route"])
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.Empty(t, requests(), "a dry run must not contact the service")
+
+	// Without the argument the write is sent
+	resp := invokeTool(t, server, "Products_create", map[string]interface{}{"Name": "Bolt"})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, requests(), "POST /Products")
+}
+
+// TestDryRunMode tests that --dry-run covers update, delete and functions but not reads
+func TestDryRunMode(t *testing.T) {
+	server, requests := newTestBridge(t, writeProtectionMetadata, &config.Config{DryRun: true})
+
+	request := dryRunRequest(t, toolText(t, server, "Products_update", map[string]interface{}{"ID": "1", "_method": "MERGE"}))
+	assert.Equal(t, "MERGE", request["method"])
+	assert.True(t, strings.HasSuffix(request["url"].(string), "/Products('1')"))
+
+	request = dryRunRequest(t, toolText(t, server, "Orders_delete", map[string]interface{}{"OrderID": "7"}))
+	assert.Equal(t, "DELETE", request["method"])
+	assert.Nil(t, request["body"])
+
+	request = dryRunRequest(t, toolText(t, server, "ReleaseOrder", nil))
+	assert.Equal(t, "POST", request["method"])
+	assert.True(t, strings.HasSuffix(request["url"].(string), "/ReleaseOrder"))
+	assert.Empty(t, requests())
+
+	// Read tools still query the service
+	resp := invokeTool(t, server, "Products_get", map[string]interface{}{"ID": "1"})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, []string{"GET /Products('1')"}, requests())
+}
+
+// TestDryRunBatch tests that batches honour $dry_run and --dry-run, also with confirmations
+func TestDryRunBatch(t *testing.T) {
+	operations := []interface{}{
+		map[string]interface{}{"operation": "create", "entity_set": "Products", "data": map[string]interface{}{"Name": "Bolt"}},
+		map[string]interface{}{"operation": "delete", "entity_set": "Products", "key": map[string]interface{}{"ID": "1"}},
+	}
+
+	for name, cfg := range map[string]*config.Config{
+		"argument":     {},
+		"confirm":      {ConfirmWrites: true},
+		"mode":         {DryRun: true},
+		"mode confirm": {DryRun: true, ConfirmWrites: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, requests := newTestBridge(t, overridesMetadata, cfg)
+
+			args := map[string]interface{}{"operations": operations}
+			if !cfg.DryRun {
+				args["$dry_run"] = true
+			}
+			request := dryRunRequest(t, toolText(t, server, "odata_batch", args))
+			assert.Equal(t, "POST", request["method"])
+			assert.True(t, strings.HasSuffix(request["url"].(string), "/$batch"))
+			assert.Contains(t, request["body"], "DELETE Products('1')")
+			assert.Empty(t, requests(), "a dry run must not contact the service")
+		})
+	}
+}
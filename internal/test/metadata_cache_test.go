@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/config"
+)
+
+// newMetadataServer serves navigationV2Metadata with an ETag and records the
+// If-None-Match header of each $metadata request
+func newMetadataServer(t *testing.T) (*httptest.Server, func() []string) {
+	var (
+		mu         sync.Mutex
+		validators []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		validators = append(validators, r.Header.Get("If-None-Match"))
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(navigationV2Metadata))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), validators...)
+	}
+}
+
+// TestMetadataCacheRevalidation tests that cached metadata is revalidated with its ETag
+func TestMetadataCacheRevalidation(t *testing.T) {
+	server, validators := newMetadataServer(t)
+	cache, err := client.NewMetadataCache(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		odataClient := client.NewODataClient(server.URL, false)
+		odataClient.SetMetadataCache(cache)
+		meta, err := odataClient.GetMetadata(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, meta.EntitySets, "Orders")
+	}
+	assert.Equal(t, []string{"", `"v1"`}, validators())
+}
+
+// TestMetadataCacheTTL tests that fresh cached metadata is used without contacting the service
+func TestMetadataCacheTTL(t *testing.T) {
+	server, validators := newMetadataServer(t)
+	cache, err := client.NewMetadataCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		odataClient := client.NewODataClient(server.URL, false)
+		odataClient.SetMetadataCache(cache)
+		_, err := odataClient.GetMetadata(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Len(t, validators(), 1)
+}
+
+// TestMetadataCacheOffline tests that cached metadata is used when the service is unreachable
+func TestMetadataCacheOffline(t *testing.T) {
+	server, _ := newMetadataServer(t)
+	dir := t.TempDir()
+	cfg := &config.Config{ServiceURL: server.URL, MetadataCacheDir: dir}
+
+	_, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+	server.Close()
+
+	offline, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+	info, err := offline.GetTraceInfo()
+	require.NoError(t, err)
+	assert.Greater(t, info.TotalTools, 0)
+
+	_, err = bridge.NewODataMCPBridge(&config.Config{ServiceURL: server.URL})
+	assert.Error(t, err)
+}
+
+// TestMetadataFile tests that tools are generated from a local EDMX file without contacting the service
+func TestMetadataFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.xml")
+	require.NoError(t, os.WriteFile(path, []byte(navigationV4Metadata), 0o600))
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{
+		ServiceURL:   "http://127.0.0.1:1/odata/",
+		MetadataFile: path,
+		NoPostfix:    true,
+	})
+	require.NoError(t, err)
+
+	info, err := odataBridge.GetTraceInfo()
+	require.NoError(t, err)
+	names := make([]string, 0, len(info.RegisteredTools))
+	for _, tool := range info.RegisteredTools {
+		names = append(names, tool.Name)
+	}
+	assert.Contains(t, names, "Orders_filter")
+	assert.Contains(t, names, "Orders_Items_link")
+}
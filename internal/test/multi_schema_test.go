@@ -0,0 +1,213 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const multiSchemaV2Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx">
+  <edmx:DataServices m:DataServiceVersion="2.0" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+    <Schema Namespace="com.example.sales.types" Alias="Types" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+    </Schema>
+    <Schema Namespace="com.example.sales" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Customer">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="SALES_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Orders" EntityType="Types.Order"/>
+        <EntitySet Name="Customers" EntityType="com.example.sales.Customer"/>
+        <EntitySet Name="Invoices" EntityType="com.example.billing.Invoice"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const multiSchemaV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:Reference Uri="billing/$metadata">
+    <edmx:Include Namespace="com.example.billing" Alias="Billing"/>
+  </edmx:Reference>
+  <edmx:DataServices>
+    <Schema Namespace="com.example.sales.types" Alias="Types" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+      </EntityType>
+    </Schema>
+    <Schema Namespace="com.example.sales" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityContainer Name="Container">
+        <EntitySet Name="Orders" EntityType="Types.Order"/>
+        <EntitySet Name="Invoices" EntityType="Billing.Invoice"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const billingV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="com.example.billing" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Invoice">
+        <Key><PropertyRef Name="Number"/></Key>
+        <Property Name="Number" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// sharedNamesV4Metadata defines an Order entity type and an Address complex
+// type in two namespaces
+const sharedNamesV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="com.example.sales" Alias="Sales" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="ShipTo" Type="Sales.Address"/>
+      </EntityType>
+      <ComplexType Name="Address">
+        <Property Name="Street" Type="Edm.String"/>
+      </ComplexType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Orders" EntityType="Sales.Order"/>
+        <EntitySet Name="ArchivedOrders" EntityType="com.example.archive.Order"/>
+      </EntityContainer>
+    </Schema>
+    <Schema Namespace="com.example.archive" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="Number"/></Key>
+        <Property Name="Number" Type="Edm.String" Nullable="false"/>
+        <Property Name="Addresses" Type="Collection(com.example.archive.Address)"/>
+      </EntityType>
+      <ComplexType Name="Address">
+        <Property Name="Line1" Type="Edm.String"/>
+      </ComplexType>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseMultiSchemaV2 tests alias-qualified types across schemas of a v2 document
+func TestParseMultiSchemaV2(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(multiSchemaV2Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Order", meta.EntitySets["Orders"].EntityType)
+	assert.Equal(t, "Customer", meta.EntitySets["Customers"].EntityType)
+	assert.Equal(t, map[string]string{"Invoices": "com.example.billing.Invoice"}, metadata.UnresolvedEntitySets(meta))
+}
+
+// TestParseMultiSchemaV4 tests edmx:Reference includes and alias-qualified types of a v4 document
+func TestParseMultiSchemaV4(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(multiSchemaV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Order", meta.EntitySets["Orders"].EntityType)
+	require.Len(t, meta.References, 1)
+	assert.Equal(t, "Billing", meta.References[0].Includes[0].Alias)
+
+	unresolved := metadata.UnresolvedEntitySets(meta)
+	assert.Equal(t, map[string]string{"Invoices": "Billing.Invoice"}, unresolved)
+	assert.Equal(t, []string{"billing/$metadata"}, metadata.ReferencesFor(meta, unresolved))
+
+	require.NoError(t, metadata.MergeReferencedTypes(meta, []byte(billingV4Metadata)))
+	assert.Equal(t, "Invoice", meta.EntitySets["Invoices"].EntityType)
+	assert.Empty(t, metadata.UnresolvedEntitySets(meta))
+}
+
+// TestReferencedMetadataFetch tests that the client loads referenced documents from the service host
+func TestReferencedMetadataFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		switch r.URL.Path {
+		case "/sales/$metadata":
+			w.Write([]byte(multiSchemaV4Metadata))
+		case "/sales/billing/$metadata":
+			w.Write([]byte(billingV4Metadata))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	meta, err := client.NewODataClient(server.URL+"/sales/", false).GetMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Invoice", meta.EntitySets["Invoices"].EntityType)
+	assert.Contains(t, meta.EntityTypes, "Invoice")
+}
+
+// TestTraceUnresolvedEntitySets tests that sets with unknown types are reported in the trace info
+func TestTraceUnresolvedEntitySets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(multiSchemaV2Metadata))
+	}))
+	defer server.Close()
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: server.URL + "/"})
+	require.NoError(t, err)
+
+	info, err := odataBridge.GetTraceInfo()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Invoices (com.example.billing.Invoice)"}, info.UnresolvedSets)
+}
+
+// TestParseSharedTypeNames tests that types of the same name in two
+// namespaces are keyed by qualified name instead of replacing each other
+func TestParseSharedTypeNames(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(sharedNamesV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	assert.NotContains(t, meta.EntityTypes, "Order")
+	assert.Equal(t, "com.example.sales.Order", meta.EntitySets["Orders"].EntityType)
+	assert.Equal(t, "com.example.archive.Order", meta.EntitySets["ArchivedOrders"].EntityType)
+	assert.Equal(t, []string{"ID"}, meta.EntityTypes["com.example.sales.Order"].KeyProperties)
+	assert.Equal(t, []string{"Number"}, meta.EntityTypes["com.example.archive.Order"].KeyProperties)
+	assert.Empty(t, metadata.UnresolvedEntitySets(meta))
+
+	assert.Contains(t, meta.ComplexTypes, "com.example.sales.Address")
+	assert.Contains(t, meta.ComplexTypes, "com.example.archive.Address")
+	assert.Equal(t, "com.example.sales.Address", meta.EntityTypes["com.example.sales.Order"].Properties[1].Type)
+	assert.Equal(t, "Collection(com.example.archive.Address)", meta.EntityTypes["com.example.archive.Order"].Properties[1].Type)
+
+	schemas := toolSchemas(t, sharedNamesV4Metadata)
+	assert.Contains(t, schemas["Orders_get"], "ID")
+	assert.Contains(t, schemas["ArchivedOrders_get"], "Number")
+	shipTo := schemas["Orders_create"]["ShipTo"]["properties"].(map[string]interface{})
+	assert.Contains(t, shipTo, "Street")
+	assert.NotContains(t, shipTo, "Line1")
+}
+
+// TestMergeReferencedSharedTypeName tests that a referenced type does not
+// replace a type of the same name in the service document
+func TestMergeReferencedSharedTypeName(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(multiSchemaV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	billing := strings.Replace(billingV4Metadata, `</Schema>`, `<EntityType Name="Order">
+        <Key><PropertyRef Name="Reference"/></Key>
+        <Property Name="Reference" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+    </Schema>`, 1)
+	require.NoError(t, metadata.MergeReferencedTypes(meta, []byte(billing)))
+
+	assert.Equal(t, []string{"ID"}, meta.EntityTypes["Order"].KeyProperties)
+	assert.Equal(t, []string{"Reference"}, meta.EntityTypes["com.example.billing.Order"].KeyProperties)
+	assert.Equal(t, "Invoice", meta.EntitySets["Invoices"].EntityType)
+}
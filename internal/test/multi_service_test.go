@@ -0,0 +1,124 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// newRecordingService starts a mock OData service that records the Authorization header of data requests
+func newRecordingService(t *testing.T) (*httptest.Server, func() []string) {
+	var (
+		mu    sync.Mutex
+		auths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(passThroughMetadata))
+			return
+		}
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), auths...)
+	}
+}
+
+// invokeTool sends a tools/call request to the MCP server and returns the JSON-RPC response
+func invokeTool(t *testing.T, server *mcp.Server, name string, args map[string]interface{}) *transport.Message {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	params, err := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	require.NoError(t, err)
+	resp, err := server.HandleMessage(context.Background(), &transport.Message{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(`1`),
+		Method:  "tools/call",
+		Params:  params,
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+// callTool invokes a tool without arguments and returns the decoded result
+func callTool(t *testing.T, server *mcp.Server, name string) map[string]interface{} {
+	resp := invokeTool(t, server, name, nil)
+	require.Nil(t, resp.Error)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	return result
+}
+
+// TestMultiServiceBridge tests that several services share one MCP server with their own auth and naming
+func TestMultiServiceBridge(t *testing.T) {
+	sales, salesAuth := newRecordingService(t)
+	material, materialAuth := newRecordingService(t)
+
+	multi, err := bridge.NewMultiServiceBridge([]*config.Config{
+		{Name: "sales", ServiceURL: sales.URL + "/", Username: "alice", Password: "secret", ToolPostfix: "sales"},
+		{Name: "material", ServiceURL: material.URL + "/", BearerToken: "material-token", ToolPostfix: "material"},
+	})
+	require.NoError(t, err)
+	require.Len(t, multi.Services(), 2)
+
+	server := multi.GetServer()
+	names := make(map[string]bool)
+	for _, tool := range server.GetTools() {
+		names[tool.Name] = true
+	}
+	assert.True(t, names["filter_Products_sales"])
+	assert.True(t, names["filter_Products_material"])
+	assert.True(t, names["odata_service_info_sales"])
+	assert.True(t, names["odata_service_info_material"])
+
+	callTool(t, server, "filter_Products_sales")
+	callTool(t, server, "filter_Products_material")
+	assert.Equal(t, []string{"Basic YWxpY2U6c2VjcmV0"}, salesAuth())
+	assert.Equal(t, []string{"Bearer material-token"}, materialAuth())
+
+	result := callTool(t, server, "odata_service_info_material")
+	content := result["content"].([]interface{})
+	text := content[0].(map[string]interface{})["text"].(string)
+	assert.Contains(t, text, `"service_name":"material"`)
+	assert.Contains(t, text, material.URL)
+
+	infos, err := multi.GetTraceInfo()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "sales", infos[0].ServiceName)
+	assert.Equal(t, "material", infos[1].ServiceName)
+}
+
+// TestMultiServiceToolCollision tests that services generating the same tool names are rejected
+func TestMultiServiceToolCollision(t *testing.T) {
+	first, _ := newRecordingService(t)
+	second, _ := newRecordingService(t)
+
+	_, err := bridge.NewMultiServiceBridge([]*config.Config{
+		{Name: "a", ServiceURL: first.URL + "/", ToolPostfix: "erp"},
+		{Name: "b", ServiceURL: second.URL + "/", ToolPostfix: "erp"},
+	})
+	assert.ErrorContains(t, err, "tool_postfix")
+}
@@ -0,0 +1,163 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const navigationV2Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx">
+  <edmx:DataServices m:DataServiceVersion="2.0" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+    <Schema Namespace="SALES" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <NavigationProperty Name="Items" Relationship="SALES.OrderItems" FromRole="FromOrder" ToRole="ToItems"/>
+        <NavigationProperty Name="Customer" Relationship="SALES.OrderCustomer" FromRole="FromOrder" ToRole="ToCustomer"/>
+      </EntityType>
+      <EntityType Name="Item">
+        <Key><PropertyRef Name="ID"/><PropertyRef Name="Pos"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Pos" Type="Edm.Int32" Nullable="false"/>
+      </EntityType>
+      <EntityType Name="Customer">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <Association Name="OrderItems">
+        <End Type="SALES.Order" Multiplicity="1" Role="FromOrder"/>
+        <End Type="SALES.Item" Multiplicity="*" Role="ToItems"/>
+      </Association>
+      <Association Name="OrderCustomer">
+        <End Type="SALES.Order" Multiplicity="*" Role="FromOrder"/>
+        <End Type="SALES.Customer" Multiplicity="1" Role="ToCustomer"/>
+      </Association>
+      <EntityContainer Name="SALES_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Orders" EntityType="SALES.Order"/>
+        <EntitySet Name="OrderItems" EntityType="SALES.Item"/>
+        <EntitySet Name="ArchivedItems" EntityType="SALES.Item"/>
+        <EntitySet Name="Customers" EntityType="SALES.Customer"/>
+        <AssociationSet Name="OrderItemsSet" Association="SALES.OrderItems">
+          <End EntitySet="Orders" Role="FromOrder"/>
+          <End EntitySet="OrderItems" Role="ToItems"/>
+        </AssociationSet>
+        <AssociationSet Name="OrderCustomerSet" Association="SALES.OrderCustomer">
+          <End EntitySet="Orders" Role="FromOrder"/>
+          <End EntitySet="Customers" Role="ToCustomer"/>
+        </AssociationSet>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const navigationV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Sales" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <NavigationProperty Name="Items" Type="Collection(Sales.Item)"/>
+      </EntityType>
+      <EntityType Name="Item">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Orders" EntityType="Sales.Order">
+          <NavigationPropertyBinding Path="Items" Target="OrderItems"/>
+        </EntitySet>
+        <EntitySet Name="OrderItems" EntityType="Sales.Item"/>
+        <EntitySet Name="ArchivedItems" EntityType="Sales.Item"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseNavigationTargets tests that associations and bindings resolve navigation targets
+func TestParseNavigationTargets(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(navigationV2Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	items := meta.EntityTypes["Order"].NavigationProps[0]
+	assert.Equal(t, "Item", items.TargetType)
+	assert.True(t, items.IsCollection)
+	customer := meta.EntityTypes["Order"].NavigationProps[1]
+	assert.False(t, customer.IsCollection)
+	assert.Equal(t, map[string]string{"Items": "OrderItems", "Customer": "Customers"}, meta.EntitySets["Orders"].NavigationTargets)
+
+	meta, err = metadata.ParseMetadata([]byte(navigationV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+	assert.True(t, meta.EntityTypes["Order"].NavigationProps[0].IsCollection)
+	assert.Equal(t, "OrderItems", meta.EntitySets["Orders"].NavigationTargets["Items"])
+}
+
+// TestNavigationTools tests reading and linking through navigation properties of a v2 service
+func TestNavigationTools(t *testing.T) {
+	schemas := toolSchemas(t, navigationV2Metadata)
+	require.Contains(t, schemas, "Orders_Items_filter")
+	assert.Contains(t, schemas["Orders_Items_filter"], "ID")
+	assert.Contains(t, schemas["Orders_Items_filter"], "$top")
+	require.Contains(t, schemas, "Orders_Customer_get")
+	assert.NotContains(t, schemas, "Orders_Customer_filter")
+	assert.Contains(t, schemas["Orders_Items_link"], "target_key")
+	assert.NotContains(t, schemas["Orders_Customer_unlink"], "target_key")
+
+	server, requests := newTestBridge(t, navigationV2Metadata, &config.Config{})
+
+	toolText(t, server, "Orders_Items_filter", map[string]interface{}{"ID": "4711", "$top": 5})
+	toolText(t, server, "Orders_Customer_get", map[string]interface{}{"ID": "4711"})
+	assert.Equal(t, []string{"GET /Orders('4711')/Items", "GET /Orders('4711')/Customer"}, requests())
+
+	link := dryRunRequest(t, toolText(t, server, "Orders_Items_link", map[string]interface{}{
+		"$dry_run":   true,
+		"ID":         "4711",
+		"target_key": map[string]interface{}{"ID": "4711", "Pos": 10},
+	}))
+	assert.Equal(t, "POST", link["method"])
+	assert.Contains(t, link["url"], "/Orders('4711')/$links/Items")
+	assert.Contains(t, link["body"].(map[string]interface{})["uri"], "/OrderItems(")
+
+	unlink := dryRunRequest(t, toolText(t, server, "Orders_Customer_unlink", map[string]interface{}{
+		"$dry_run": true,
+		"ID":       "4711",
+	}))
+	assert.Equal(t, "DELETE", unlink["method"])
+	assert.Contains(t, unlink["url"], "/Orders('4711')/$links/Customer")
+}
+
+// TestNavigationToolsV4 tests $ref links and NavigationPropertyBinding targets of a v4 service
+func TestNavigationToolsV4(t *testing.T) {
+	server, _ := newTestBridge(t, navigationV4Metadata, &config.Config{})
+
+	link := dryRunRequest(t, toolText(t, server, "Orders_Items_link", map[string]interface{}{
+		"$dry_run":   true,
+		"ID":         1,
+		"target_key": map[string]interface{}{"ID": 2},
+	}))
+	assert.Equal(t, "POST", link["method"])
+	assert.Contains(t, link["url"], "/Orders(1)/Items/$ref")
+	assert.Contains(t, link["body"].(map[string]interface{})["@odata.id"], "/OrderItems(2)")
+
+	unlink := dryRunRequest(t, toolText(t, server, "Orders_Items_unlink", map[string]interface{}{
+		"$dry_run":   true,
+		"ID":         1,
+		"target_key": map[string]interface{}{"ID": 2},
+	}))
+	assert.Contains(t, unlink["url"], "/Orders(1)/Items(2)/$ref")
+}
+
+// TestNavigationToolsReadOnly tests that read-only mode keeps navigation reads but hides link tools
+func TestNavigationToolsReadOnly(t *testing.T) {
+	server, _ := newTestBridge(t, navigationV2Metadata, &config.Config{ReadOnly: true})
+
+	resp := invokeTool(t, server, "Orders_Items_link", map[string]interface{}{"ID": "1", "target_key": map[string]interface{}{"ID": "1", "Pos": 1}})
+	require.NotNil(t, resp.Error)
+
+	resp = invokeTool(t, server, "Orders_Items_filter", map[string]interface{}{"ID": "1"})
+	assert.Nil(t, resp.Error)
+}
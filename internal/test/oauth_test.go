@@ -0,0 +1,195 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/client"
+)
+
+// tokenEndpoint is a local OAuth2 token endpoint that issues numbered tokens
+type tokenEndpoint struct {
+	mu        sync.Mutex
+	expiresIn int
+	requests  []map[string]string
+	server    *httptest.Server
+}
+
+func newTokenEndpoint(t *testing.T, expiresIn int) *tokenEndpoint {
+	te := &tokenEndpoint{expiresIn: expiresIn}
+	te.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		te.mu.Lock()
+		form := make(map[string]string)
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+		te.requests = append(te.requests, form)
+		n := len(te.requests)
+		te.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if form["client_secret"] == "wrong" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"Bad client credentials"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("token-%d", n),
+			"token_type":    "bearer",
+			"expires_in":    te.expiresIn,
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+		})
+	}))
+	t.Cleanup(te.server.Close)
+	return te
+}
+
+func (te *tokenEndpoint) grants() []map[string]string {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	return append([]map[string]string(nil), te.requests...)
+}
+
+// newBearerService starts an OData service that records bearer tokens and rejects those in revoked
+func newBearerService(t *testing.T, revoked ...string) (*httptest.Server, func() []string) {
+	var (
+		mu     sync.Mutex
+		tokens []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		mu.Lock()
+		tokens = append(tokens, auth)
+		mu.Unlock()
+
+		for _, token := range revoked {
+			if auth == "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), tokens...)
+	}
+}
+
+// TestOAuth2ClientCredentials tests that a token is acquired once and reused until it nears expiry
+func TestOAuth2ClientCredentials(t *testing.T) {
+	te := newTokenEndpoint(t, 3600)
+	service, seen := newBearerService(t)
+
+	odataClient := client.NewODataClient(service.URL, false)
+	odataClient.SetTokenSource(client.NewOAuth2TokenSource(client.OAuth2Config{
+		TokenURL:     te.server.URL,
+		ClientID:     "my-client",
+		ClientSecret: "my-secret",
+		Scopes:       []string{"api.read", "api.write"},
+	}, false))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := odataClient.GetEntitySet(ctx, "Products", nil)
+		require.NoError(t, err)
+	}
+
+	grants := te.grants()
+	require.Len(t, grants, 1)
+	assert.Equal(t, "client_credentials", grants[0]["grant_type"])
+	assert.Equal(t, "my-client", grants[0]["client_id"])
+	assert.Equal(t, "my-secret", grants[0]["client_secret"])
+	assert.Equal(t, "api.read api.write", grants[0]["scope"])
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, seen())
+}
+
+// TestOAuth2RefreshBeforeExpiry tests that tokens about to expire are renewed with the rotated refresh token
+func TestOAuth2RefreshBeforeExpiry(t *testing.T) {
+	// Tokens valid for less than the renewal margin are renewed on every use
+	te := newTokenEndpoint(t, 30)
+	service, seen := newBearerService(t)
+
+	odataClient := client.NewODataClient(service.URL, false)
+	odataClient.SetTokenSource(client.NewOAuth2TokenSource(client.OAuth2Config{
+		TokenURL:     te.server.URL,
+		ClientID:     "my-client",
+		RefreshToken: "initial-refresh",
+	}, false))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := odataClient.GetEntitySet(ctx, "Products", nil)
+		require.NoError(t, err)
+	}
+
+	grants := te.grants()
+	require.Len(t, grants, 2)
+	assert.Equal(t, "refresh_token", grants[0]["grant_type"])
+	assert.Equal(t, "initial-refresh", grants[0]["refresh_token"])
+	assert.Equal(t, "refresh-1", grants[1]["refresh_token"])
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, seen())
+}
+
+// TestOAuth2RetryOnUnauthorized tests that a rejected token is replaced and the request retried once
+func TestOAuth2RetryOnUnauthorized(t *testing.T) {
+	te := newTokenEndpoint(t, 3600)
+	service, seen := newBearerService(t, "token-1")
+
+	odataClient := client.NewODataClient(service.URL, false)
+	odataClient.SetTokenSource(client.NewOAuth2TokenSource(client.OAuth2Config{
+		TokenURL:     te.server.URL,
+		ClientID:     "my-client",
+		ClientSecret: "my-secret",
+	}, false))
+
+	_, err := odataClient.GetEntitySet(context.Background(), "Products", nil)
+	require.NoError(t, err)
+
+	assert.Len(t, te.grants(), 2)
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, seen())
+}
+
+// TestOAuth2TokenEndpointError tests that token endpoint errors are reported
+func TestOAuth2TokenEndpointError(t *testing.T) {
+	te := newTokenEndpoint(t, 3600)
+	service, seen := newBearerService(t)
+
+	odataClient := client.NewODataClient(service.URL, false)
+	odataClient.SetTokenSource(client.NewOAuth2TokenSource(client.OAuth2Config{
+		TokenURL:     te.server.URL,
+		ClientID:     "my-client",
+		ClientSecret: "wrong",
+	}, false))
+
+	_, err := odataClient.GetEntitySet(context.Background(), "Products", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_client")
+	assert.Contains(t, err.Error(), "Bad client credentials")
+	assert.Empty(t, seen())
+}
+
+// TestStaticBearerToken tests that a static bearer token is sent as is
+func TestStaticBearerToken(t *testing.T) {
+	service, seen := newBearerService(t)
+
+	odataClient := client.NewODataClient(service.URL, false)
+	odataClient.SetBearerToken("static-token")
+
+	_, err := odataClient.GetEntitySet(context.Background(), "Products", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer static-token"}, seen())
+}
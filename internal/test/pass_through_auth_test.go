@@ -0,0 +1,168 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/transport"
+	mcphttp "github.com/zmcp/odata-mcp/internal/transport/http"
+)
+
+const passThroughMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" Version="1.0">
+  <edmx:DataServices>
+    <Schema xmlns="http://schemas.microsoft.com/ado/2008/09/edm" Namespace="TestNamespace">
+      <EntityType Name="Product">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="TestContainer">
+        <EntitySet Name="Products" EntityType="TestNamespace.Product"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// backendCall records the identity an OData request was sent with
+type backendCall struct {
+	authorization string
+	ssoCookie     string
+}
+
+// newPassThroughBridge starts a mock OData service and a pass-through Streamable HTTP transport in front of a bridge
+func newPassThroughBridge(t *testing.T) (*httptest.Server, func() []backendCall) {
+	var (
+		mu    sync.Mutex
+		calls []backendCall
+	)
+	odata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(passThroughMetadata))
+			return
+		}
+
+		call := backendCall{authorization: r.Header.Get("Authorization")}
+		if cookie, err := r.Cookie("MYSAPSSO2"); err == nil {
+			call.ssoCookie = cookie.Value
+		}
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	t.Cleanup(odata.Close)
+
+	cfg := &config.Config{
+		ServiceURL: odata.URL + "/",
+		Username:   "service-user",
+		Password:   "secret",
+		NoPostfix:  true,
+	}
+	odataBridge, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+
+	server := odataBridge.GetServer()
+	trans := mcphttp.NewStreamableHTTP("", func(ctx context.Context, msg *transport.Message) (*transport.Message, error) {
+		return server.HandleMessage(ctx, msg)
+	})
+	trans.SetPassThroughAuth([]string{"MYSAPSSO2"})
+	server.SetTransport(trans)
+
+	ts := httptest.NewServer(trans)
+	t.Cleanup(ts.Close)
+
+	return ts, func() []backendCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]backendCall(nil), calls...)
+	}
+}
+
+// postWithIdentity sends a JSON-RPC message with the given Authorization header and SSO cookie
+func postWithIdentity(t *testing.T, url, sessionID, authorization, ssoCookie, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if sessionID != "" {
+		req.Header.Set(mcphttp.SessionIDHeader, sessionID)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if ssoCookie != "" {
+		req.AddCookie(&http.Cookie{Name: "MYSAPSSO2", Value: ssoCookie})
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+// TestPassThroughAuthForwardsCaller tests that tool calls reach the OData service as the calling end user
+func TestPassThroughAuthForwardsCaller(t *testing.T) {
+	ts, backendCalls := newPassThroughBridge(t)
+
+	const filterCall = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"Products_filter","arguments":{}}}`
+
+	// Authorization header of the caller replaces the configured basic auth
+	resp := postWithIdentity(t, ts.URL, "", "Bearer alice-token", "",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	aliceSession := resp.Header.Get(mcphttp.SessionIDHeader)
+
+	resp = postWithIdentity(t, ts.URL, aliceSession, "Bearer alice-token", "", filterCall)
+	var msg transport.Message
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&msg))
+	resp.Body.Close()
+	require.Nil(t, msg.Error)
+
+	// A configured cookie is forwarded without any configured credentials
+	resp = postWithIdentity(t, ts.URL, "", "", "bob-ticket",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	bobSession := resp.Header.Get(mcphttp.SessionIDHeader)
+
+	resp = postWithIdentity(t, ts.URL, bobSession, "", "bob-ticket", filterCall)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	calls := backendCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, backendCall{authorization: "Bearer alice-token"}, calls[0])
+	assert.Equal(t, backendCall{ssoCookie: "bob-ticket"}, calls[1])
+}
+
+// TestPassThroughAuthRequiresCredentials tests that callers without credentials are rejected
+func TestPassThroughAuthRequiresCredentials(t *testing.T) {
+	ts, backendCalls := newPassThroughBridge(t)
+
+	resp := postWithIdentity(t, ts.URL, "", "", "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(mcphttp.SessionIDHeader))
+
+	// Cookies that are not configured for pass-through do not count as credentials
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "OTHER", Value: "x"})
+	other, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	other.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, other.StatusCode)
+
+	assert.Empty(t, backendCalls())
+}
@@ -0,0 +1,155 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+const writeProtectionMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" Version="1.0">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema xmlns="http://schemas.microsoft.com/ado/2008/09/edm" Namespace="TestNamespace">
+      <EntityType Name="Product">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="TestContainer" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Products" EntityType="TestNamespace.Product"/>
+        <EntitySet Name="Orders" EntityType="TestNamespace.Order"/>
+        <FunctionImport Name="GetStatus" ReturnType="Edm.String" m:HttpMethod="GET"/>
+        <FunctionImport Name="ReleaseOrder" ReturnType="Edm.String" m:HttpMethod="POST"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// newWriteProtectedBridge creates a bridge for a service with two entity sets and a GET and a POST function
+func newWriteProtectedBridge(t *testing.T, cfg *config.Config) map[string]bool {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(writeProtectionMetadata))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg.ServiceURL = server.URL + "/"
+	cfg.NoPostfix = true
+	odataBridge, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+
+	tools := make(map[string]bool)
+	for _, tool := range odataBridge.GetServer().GetTools() {
+		tools[tool.Name] = true
+	}
+	return tools
+}
+
+// TestReadOnlyMode tests that read-only mode removes all write tools including POST functions
+func TestReadOnlyMode(t *testing.T) {
+	tools := newWriteProtectedBridge(t, &config.Config{})
+	assert.True(t, tools["Products_create"])
+	assert.True(t, tools["ReleaseOrder"])
+
+	tools = newWriteProtectedBridge(t, &config.Config{ReadOnly: true})
+	for _, entitySet := range []string{"Products", "Orders"} {
+		assert.True(t, tools[entitySet+"_filter"])
+		assert.True(t, tools[entitySet+"_count"])
+		assert.True(t, tools[entitySet+"_get"])
+		assert.False(t, tools[entitySet+"_create"])
+		assert.False(t, tools[entitySet+"_update"])
+		assert.False(t, tools[entitySet+"_delete"])
+	}
+	assert.True(t, tools["GetStatus"])
+	assert.False(t, tools["ReleaseOrder"])
+
+	tools = newWriteProtectedBridge(t, &config.Config{NoPostFunctions: true})
+	assert.True(t, tools["Products_create"])
+	assert.True(t, tools["GetStatus"])
+	assert.False(t, tools["ReleaseOrder"])
+}
+
+// TestEntityOperationRules tests allowlists and denylists per entity set
+func TestEntityOperationRules(t *testing.T) {
+	rules, err := config.ParseOperationRules([]string{"Orders:get,filter", "Prod*:!delete,!update"})
+	require.NoError(t, err)
+
+	tools := newWriteProtectedBridge(t, &config.Config{OperationRules: rules})
+	assert.True(t, tools["Orders_get"])
+	assert.True(t, tools["Orders_filter"])
+	assert.False(t, tools["Orders_count"])
+	assert.False(t, tools["Orders_create"])
+	assert.False(t, tools["Orders_delete"])
+
+	assert.True(t, tools["Products_create"])
+	assert.True(t, tools["Products_get"])
+	assert.False(t, tools["Products_update"])
+	assert.False(t, tools["Products_delete"])
+}
+
+// TestParseOperationRulesErrors tests that malformed rules are rejected
+func TestParseOperationRulesErrors(t *testing.T) {
+	for _, def := range []string{"Orders", ":get", "Orders:", "Orders:purge", "Orders:!erase"} {
+		_, err := config.ParseOperationRules([]string{def})
+		assert.Error(t, err, def)
+	}
+}
+
+// TestBatchReferenceRules tests that Content-ID references in batches are
+// checked against the entity set their navigation property leads to
+func TestBatchReferenceRules(t *testing.T) {
+	batch := func(server *mcp.Server, ref string, data map[string]interface{}) *transport.Message {
+		return invokeTool(t, server, "odata_batch", map[string]interface{}{"operations": []interface{}{
+			map[string]interface{}{"operation": "create", "entity_set": "Orders", "data": map[string]interface{}{"ID": "1"}},
+			map[string]interface{}{"operation": "create", "entity_set": ref, "data": data},
+		}})
+	}
+
+	server, requests := newTestBridge(t, navigationV2Metadata, &config.Config{ReadOnly: true})
+	resp := invokeTool(t, server, "odata_batch", map[string]interface{}{"operations": []interface{}{
+		map[string]interface{}{"operation": "create", "entity_set": "$1/../Products"},
+	}})
+	require.NotNil(t, resp.Error)
+	resp = batch(server, "$1/Items", nil)
+	require.NotNil(t, resp.Error)
+	assert.Empty(t, requests())
+
+	server, requests = newTestBridge(t, navigationV2Metadata, &config.Config{
+		OperationRules:  []config.OperationRule{{Entity: "OrderItems", Deny: []string{"create"}}},
+		EntityOverrides: map[string]config.EntityOverride{"Customers": {HiddenFields: []string{"Name"}}},
+	})
+	for ref, message := range map[string]string{
+		"$1/../Products": "invalid entity_set reference",
+		"$2/Items":       "does not refer to an earlier create",
+		"$1/Lines":       "has no navigation property Lines",
+		"$1/Items":       "does not allow create",
+	} {
+		resp = batch(server, ref, nil)
+		require.NotNil(t, resp.Error, ref)
+		assert.Contains(t, resp.Error.Message, message, ref)
+	}
+	resp = batch(server, "$1/Customer", map[string]interface{}{"Name": "Acme"})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "Name")
+	assert.Empty(t, requests())
+
+	// Allowed references are sent; the mock service does not answer batches
+	batch(server, "$1/Customer", map[string]interface{}{"ID": "C1"})
+	assert.Contains(t, requests(), "POST /$batch")
+}
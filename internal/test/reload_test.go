@@ -0,0 +1,173 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// reloadedV2Metadata is navigationV2Metadata with ArchivedItems replaced by
+// Prospects, a new customer property and a new function import
+var reloadedV2Metadata = strings.NewReplacer(
+	`<EntitySet Name="ArchivedItems" EntityType="SALES.Item"/>`,
+	`<EntitySet Name="Prospects" EntityType="SALES.Customer"/>
+        <FunctionImport Name="Ping" ReturnType="Edm.String" m:HttpMethod="GET"/>`,
+	`<EntityType Name="Customer">
+        <Key><PropertyRef Name="ID"/></Key>`,
+	`<EntityType Name="Customer">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="Name" Type="Edm.String"/>`,
+).Replace(navigationV2Metadata)
+
+// newReloadingBridge creates a bridge on a metadata file that the test can rewrite
+func newReloadingBridge(t *testing.T, cfg *config.Config) (*bridge.ODataMCPBridge, string) {
+	path := filepath.Join(t.TempDir(), "metadata.xml")
+	require.NoError(t, os.WriteFile(path, []byte(navigationV2Metadata), 0o600))
+
+	cfg.ServiceURL = "http://127.0.0.1:1/odata/"
+	cfg.MetadataFile = path
+	cfg.NoPostfix = true
+	odataBridge, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+	return odataBridge, path
+}
+
+// toolNames returns the names of the tools registered on an MCP server
+func toolNames(server *mcp.Server) map[string]bool {
+	names := make(map[string]bool)
+	for _, tool := range server.GetTools() {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+// TestReloadDiff tests that a reload registers, updates and removes tools and reports the changes
+func TestReloadDiff(t *testing.T) {
+	odataBridge, path := newReloadingBridge(t, &config.Config{})
+	server := odataBridge.GetServer()
+	assert.True(t, toolNames(server)["ArchivedItems_filter"])
+
+	summary, err := odataBridge.Reload(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, summary.AddedTools)
+	assert.Empty(t, summary.ChangedTools)
+	assert.Empty(t, summary.RemovedTools)
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadedV2Metadata), 0o600))
+	summary, err = odataBridge.Reload(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Prospects"}, summary.AddedEntitySets)
+	assert.Equal(t, []string{"ArchivedItems"}, summary.RemovedEntitySets)
+	assert.Equal(t, []string{"Ping"}, summary.AddedFunctionImports)
+	assert.Contains(t, summary.AddedTools, "Prospects_filter")
+	assert.Contains(t, summary.AddedTools, "Ping")
+	assert.Contains(t, summary.RemovedTools, "ArchivedItems_filter")
+	assert.Contains(t, summary.ChangedTools, "Customers_create")
+	assert.NotContains(t, summary.ChangedTools, "Orders_filter")
+
+	names := toolNames(server)
+	assert.Len(t, names, summary.TotalTools)
+	assert.True(t, names["Prospects_filter"])
+	assert.False(t, names["ArchivedItems_filter"])
+
+	info, err := odataBridge.GetTraceInfo()
+	require.NoError(t, err)
+	assert.Equal(t, summary.TotalTools, info.TotalTools)
+}
+
+// TestReloadTool tests the on-demand reload tool
+func TestReloadTool(t *testing.T) {
+	odataBridge, path := newReloadingBridge(t, &config.Config{ReloadTool: true})
+	server := odataBridge.GetServer()
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadedV2Metadata), 0o600))
+	summary := toolText(t, server, "odata_reload_metadata", nil)
+	assert.Equal(t, []interface{}{"Prospects"}, summary["added_entity_sets"])
+	assert.True(t, toolNames(server)["odata_reload_metadata"])
+	assert.True(t, toolNames(server)["Prospects_get"])
+}
+
+// TestReloadConcurrentToolCalls tests that tools and resources can be used
+// while the metadata is reloaded, run with -race to catch unsynchronized access
+func TestReloadConcurrentToolCalls(t *testing.T) {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(navigationV2Metadata))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	defer service.Close()
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{Name: "sales", ServiceURL: service.URL + "/odata/", NoPostfix: true, DryRun: true})
+	require.NoError(t, err)
+	server := odataBridge.GetServer()
+
+	calls := []*transport.Message{
+		toolCall("odata_service_info", map[string]interface{}{"include_metadata": true}),
+		toolCall("Orders_filter", map[string]interface{}{"$filter": "ID eq '1'"}),
+		toolCall("odata_batch", map[string]interface{}{"operations": []interface{}{
+			map[string]interface{}{"operation": "create", "entity_set": "Orders", "data": map[string]interface{}{"ID": "1"}},
+			map[string]interface{}{"operation": "create", "entity_set": "$1/Items", "data": map[string]interface{}{"ID": "1", "Pos": 1}},
+		}}),
+		{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "resources/list"},
+		{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "resources/read", Params: json.RawMessage(`{"uri":"odata://sales/$schema/Order"}`)},
+		{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "resources/read", Params: json.RawMessage(`{"uri":"odata://sales/Orders('1')"}`)},
+	}
+
+	for _, call := range calls {
+		resp, err := server.HandleMessage(context.Background(), call)
+		require.NoError(t, err)
+		require.Nil(t, resp.Error, call.Method)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			_, err := odataBridge.Reload(context.Background())
+			assert.NoError(t, err)
+		}
+	}()
+	for _, call := range calls {
+		wg.Add(1)
+		go func(call *transport.Message) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				_, err := server.HandleMessage(context.Background(), call)
+				assert.NoError(t, err)
+			}
+		}(call)
+	}
+	wg.Wait()
+}
+
+// toolCall builds a tools/call request
+func toolCall(name string, args map[string]interface{}) *transport.Message {
+	params, _ := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	return &transport.Message{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "tools/call", Params: params}
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// newResourceBridge creates a bridge named "sales" on navigationV2Metadata
+// that serves a service document and single entities, and records their paths
+func newResourceBridge(t *testing.T, cfg *config.Config) (*mcp.Server, func() []string) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(navigationV2Metadata))
+			return
+		}
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"d":{"EntitySets":["Orders","OrderItems","ArchivedItems","Customers"]}}`))
+			return
+		}
+		w.Write([]byte(`{"d":{"ID":"1"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg.ServiceURL = server.URL + "/"
+	cfg.Name = "sales"
+	odataBridge, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+
+	return odataBridge.GetServer(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), paths...)
+	}
+}
+
+// callMethod sends a request to the MCP server and decodes its result
+func callMethod(t *testing.T, server *mcp.Server, method string, params map[string]interface{}) (map[string]interface{}, *transport.Error) {
+	data, err := json.Marshal(params)
+	require.NoError(t, err)
+	resp, err := server.HandleMessage(context.Background(), &transport.Message{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(`1`),
+		Method:  method,
+		Params:  data,
+	})
+	require.NoError(t, err)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	return result, nil
+}
+
+// readResource reads a resource and returns its contents
+func readResource(t *testing.T, server *mcp.Server, uri string) map[string]interface{} {
+	result, rpcErr := callMethod(t, server, "resources/read", map[string]interface{}{"uri": uri})
+	require.Nil(t, rpcErr, "reading %s failed", uri)
+	contents := result["contents"].([]interface{})
+	require.Len(t, contents, 1)
+	return contents[0].(map[string]interface{})
+}
+
+// TestResourcesList tests the listed resources and the entity template
+func TestResourcesList(t *testing.T) {
+	server, _ := newResourceBridge(t, &config.Config{})
+
+	result, rpcErr := callMethod(t, server, "resources/list", nil)
+	require.Nil(t, rpcErr)
+	uris := make([]string, 0)
+	for _, resource := range result["resources"].([]interface{}) {
+		uris = append(uris, resource.(map[string]interface{})["uri"].(string))
+	}
+	assert.Equal(t, []string{
+		"odata://sales/$metadata",
+		"odata://sales/",
+		"odata://sales/$schema/Customer",
+		"odata://sales/$schema/Item",
+		"odata://sales/$schema/Order",
+	}, uris)
+
+	result, rpcErr = callMethod(t, server, "resources/templates/list", nil)
+	require.Nil(t, rpcErr)
+	templates := result["resourceTemplates"].([]interface{})
+	require.Len(t, templates, 1)
+	assert.Equal(t, "odata://sales/{EntitySet}({key})", templates[0].(map[string]interface{})["uriTemplate"])
+
+	result, rpcErr = callMethod(t, server, "initialize", map[string]interface{}{})
+	require.Nil(t, rpcErr)
+	assert.Contains(t, result["capabilities"], "resources")
+}
+
+// TestResourcesRead tests reading the metadata, service document and schema resources
+func TestResourcesRead(t *testing.T) {
+	server, _ := newResourceBridge(t, &config.Config{})
+
+	metadata := readResource(t, server, "odata://sales/$metadata")
+	assert.Equal(t, "application/xml", metadata["mimeType"])
+	assert.Equal(t, navigationV2Metadata, metadata["text"])
+
+	document := readResource(t, server, "odata://sales/")
+	assert.Contains(t, document["text"], `"Customers"`)
+
+	schemaText := readResource(t, server, "odata://sales/$schema/Item")["text"].(string)
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(schemaText), &schema))
+	assert.Equal(t, "Item", schema["title"])
+	assert.Equal(t, []interface{}{"ID", "Pos"}, schema["required"])
+	assert.Equal(t, "integer", schema["properties"].(map[string]interface{})["Pos"].(map[string]interface{})["type"])
+
+	_, rpcErr := callMethod(t, server, "resources/read", map[string]interface{}{"uri": "odata://sales/$schema/Unknown"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, -32002, rpcErr.Code)
+}
+
+// TestEntityResource tests reading single entities through the resource template
+func TestEntityResource(t *testing.T) {
+	server, paths := newResourceBridge(t, &config.Config{
+		OperationRules: []config.OperationRule{{Entity: "Customers", Deny: []string{"get"}}},
+	})
+
+	entity := readResource(t, server, "odata://sales/Orders('1')")
+	assert.Equal(t, "odata://sales/Orders('1')", entity["uri"])
+	assert.Contains(t, entity["text"], `"ID":"1"`)
+
+	readResource(t, server, "odata://sales/OrderItems(Pos=2,ID='A%2CB')")
+	requested := paths()
+	require.Len(t, requested, 2)
+	assert.Equal(t, "/Orders('1')", requested[0])
+	// Composite keys are rendered in map order
+	assert.Contains(t, []string{"/OrderItems(ID='A,B',Pos=2)", "/OrderItems(Pos=2,ID='A,B')"}, requested[1])
+
+	for _, uri := range []string{"odata://sales/Unknown('1')", "odata://sales/Customers('1')", "odata://sales/Orders"} {
+		_, rpcErr := callMethod(t, server, "resources/read", map[string]interface{}{"uri": uri})
+		require.NotNil(t, rpcErr, uri)
+		assert.Equal(t, -32002, rpcErr.Code, uri)
+	}
+
+	_, rpcErr := callMethod(t, server, "resources/read", map[string]interface{}{"uri": "odata://sales/OrderItems(ID='1')"})
+	require.NotNil(t, rpcErr)
+	assert.Contains(t, rpcErr.Message, "missing required key property")
+	assert.Len(t, paths(), 2)
+}
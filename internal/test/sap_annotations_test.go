@@ -0,0 +1,87 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const sapAnnotationsMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:DataServices m:DataServiceVersion="2.0" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+    <Schema Namespace="ZSALES_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="SalesOrder">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false" sap:label="Sales Order"/>
+        <Property Name="CompanyCode" Type="Edm.String" Nullable="false" sap:label="Company Code" sap:required-in-filter="true" sap:display-format="UpperCase"/>
+        <Property Name="Customer" Type="Edm.String" sap:label="Customer" sap:text="CustomerName"/>
+        <Property Name="CustomerName" Type="Edm.String" sap:filterable="false" sap:sortable="false" sap:creatable="false" sap:updatable="false"/>
+        <Property Name="CreatedAt" Type="Edm.DateTime" sap:updatable="false"/>
+      </EntityType>
+      <EntityContainer Name="ZSALES_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="SalesOrders" EntityType="ZSALES_SRV.SalesOrder"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseSAPPropertyAnnotations tests that property-level SAP annotations are parsed
+func TestParseSAPPropertyAnnotations(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(sapAnnotationsMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	props := meta.EntityTypes["SalesOrder"].Properties
+	require.Len(t, props, 5)
+	assert.Equal(t, "Company Code", *props[1].Description)
+	assert.True(t, props[1].RequiredInFilter)
+	assert.Equal(t, "UpperCase", props[1].DisplayFormat)
+	assert.Equal(t, "CustomerName", props[2].Text)
+	assert.True(t, props[2].Filterable)
+	assert.False(t, props[3].Filterable)
+	assert.False(t, props[3].Sortable)
+	assert.False(t, props[3].Creatable)
+	assert.False(t, props[3].Updatable)
+	assert.True(t, props[4].Creatable)
+	assert.False(t, props[4].Updatable)
+}
+
+// TestSAPAnnotationToolSchemas tests that SAP annotations shape the tool schemas
+func TestSAPAnnotationToolSchemas(t *testing.T) {
+	schemas := toolSchemas(t, sapAnnotationsMetadata)
+
+	assert.Equal(t, "Customer (text in CustomerName)", schemas["SalesOrders_create"]["Customer"]["description"])
+	assert.Equal(t, "Company Code (display format UpperCase)", schemas["SalesOrders_create"]["CompanyCode"]["description"])
+	assert.NotContains(t, schemas["SalesOrders_create"], "CustomerName")
+	assert.Contains(t, schemas["SalesOrders_create"], "CreatedAt")
+	assert.NotContains(t, schemas["SalesOrders_update"], "CustomerName")
+	assert.NotContains(t, schemas["SalesOrders_update"], "CreatedAt")
+
+	filter := schemas["SalesOrders_filter"]["$filter"]["description"].(string)
+	assert.Contains(t, filter, "Filterable properties: OrderID, CompanyCode, Customer, CreatedAt")
+	assert.Contains(t, filter, "Required filter properties: CompanyCode")
+	assert.Contains(t, schemas["SalesOrders_filter"]["$orderby"]["description"], "Sortable properties: OrderID, CompanyCode, Customer, CreatedAt")
+}
+
+// TestRequiredInFilterEnforced tests that queries without a required filter are rejected before the service is called
+func TestRequiredInFilterEnforced(t *testing.T) {
+	server, requests := newTestBridge(t, sapAnnotationsMetadata, &config.Config{})
+
+	resp := invokeTool(t, server, "SalesOrders_filter", map[string]interface{}{"$top": 5})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "CompanyCode")
+
+	resp = invokeTool(t, server, "SalesOrders_count", map[string]interface{}{"$filter": "Customer eq '1'"})
+	require.NotNil(t, resp.Error)
+
+	// A property name inside a longer name does not count
+	resp = invokeTool(t, server, "SalesOrders_filter", map[string]interface{}{"$filter": "CompanyCodeText eq 'x' and XCompanyCode eq 'y'"})
+	require.NotNil(t, resp.Error)
+	assert.Empty(t, requests())
+
+	resp = invokeTool(t, server, "SalesOrders_filter", map[string]interface{}{"$filter": "(CompanyCode eq '1000')"})
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, []string{"GET /SalesOrders"}, requests())
+}
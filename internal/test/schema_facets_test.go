@@ -0,0 +1,129 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+	"github.com/zmcp/odata-mcp/internal/models"
+)
+
+const facetsMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:sap="http://www.sap.com/Protocols/SAPData" Version="1.0">
+  <edmx:DataServices>
+    <Schema xmlns="http://schemas.microsoft.com/ado/2008/09/edm" Namespace="TestNamespace">
+      <EntityType Name="Material">
+        <Key><PropertyRef Name="MaterialID"/></Key>
+        <Property Name="MaterialID" Type="Edm.String" Nullable="false" MaxLength="18" sap:label="Material"/>
+        <Property Name="Description" Type="Edm.String" MaxLength="40" sap:label="Material Description"/>
+        <Property Name="Price" Type="Edm.Decimal" Nullable="false" Precision="13" Scale="2"/>
+        <Property Name="Weight" Type="Edm.Decimal" Precision="5" Scale="0"/>
+        <Property Name="Rate" Type="Edm.Decimal" Precision="3" Scale="3"/>
+        <Property Name="Level" Type="Edm.Byte" Nullable="false"/>
+        <Property Name="Stock" Type="Edm.Int32"/>
+        <Property Name="Guid" Type="Edm.Guid"/>
+        <Property Name="ChangedAt" Type="Edm.DateTime"/>
+        <Property Name="Picture" Type="Edm.Binary"/>
+      </EntityType>
+      <EntityContainer Name="TestContainer">
+        <EntitySet Name="Materials" EntityType="TestNamespace.Material"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// toolSchemas returns the JSON encoded input schema properties of all tools
+func toolSchemas(t *testing.T, metadata string) map[string]map[string]map[string]interface{} {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(metadata))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: server.URL + "/", NoPostfix: true})
+	require.NoError(t, err)
+
+	schemas := make(map[string]map[string]map[string]interface{})
+	for _, tool := range odataBridge.GetServer().GetTools() {
+		data, err := json.Marshal(tool.InputSchema)
+		require.NoError(t, err)
+		var schema struct {
+			Properties map[string]map[string]interface{} `json:"properties"`
+		}
+		require.NoError(t, json.Unmarshal(data, &schema))
+		schemas[tool.Name] = schema.Properties
+	}
+	return schemas
+}
+
+// TestParseFacets tests that the v2 parser keeps facets and SAP labels
+func TestParseFacets(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(facetsMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	props := make(map[string]*models.EntityProperty)
+	for _, prop := range meta.EntityTypes["Material"].Properties {
+		props[prop.Name] = prop
+	}
+	assert.Equal(t, 18, props["MaterialID"].MaxLength)
+	require.NotNil(t, props["Description"].Description)
+	assert.Equal(t, "Material Description", *props["Description"].Description)
+	assert.Equal(t, 13, props["Price"].Precision)
+	assert.Equal(t, 2, props["Price"].Scale)
+	assert.Nil(t, props["Stock"].Description)
+}
+
+// TestFacetSchemas tests the JSON Schemas generated from EDM facets
+func TestFacetSchemas(t *testing.T) {
+	schemas := toolSchemas(t, facetsMetadata)
+
+	create := schemas["Materials_create"]
+	require.NotNil(t, create)
+
+	assert.Equal(t, []interface{}{"string", "null"}, create["Description"]["type"])
+	assert.Equal(t, float64(40), create["Description"]["maxLength"])
+	assert.Equal(t, "Material Description", create["Description"]["description"])
+
+	assert.Equal(t, []interface{}{"number", "string"}, create["Price"]["type"])
+	assert.Equal(t, `^-?\d{1,11}(\.\d{1,2})?$`, create["Price"]["pattern"])
+	assert.Equal(t, `^-?\d{1,5}$`, create["Weight"]["pattern"])
+
+	// Decimals without integer digits still require a digit
+	rate := regexp.MustCompile(create["Rate"]["pattern"].(string))
+	for value, valid := range map[string]bool{"0": true, "0.125": true, ".5": true, "-0.1": true, "": false, "-": false, "0.": false, "1.5": false, "0.1234": false} {
+		assert.Equal(t, valid, rate.MatchString(value), value)
+	}
+
+	assert.Equal(t, "integer", create["Level"]["type"])
+	assert.Equal(t, float64(0), create["Level"]["minimum"])
+	assert.Equal(t, float64(255), create["Level"]["maximum"])
+	assert.Equal(t, float64(2147483647), create["Stock"]["maximum"])
+
+	assert.Equal(t, "uuid", create["Guid"]["format"])
+	assert.Equal(t, "date-time", create["ChangedAt"]["format"])
+	assert.Equal(t, "byte", create["Picture"]["format"])
+	assert.Equal(t, "Property: Stock", create["Stock"]["description"])
+
+	// Keys are never null and keep their label
+	key := schemas["Materials_get"]["MaterialID"]
+	assert.Equal(t, "string", key["type"])
+	assert.Equal(t, float64(18), key["maxLength"])
+	assert.Equal(t, "Key property: MaterialID (Material)", key["description"])
+
+	update := schemas["Materials_update"]
+	assert.Equal(t, "string", update["MaterialID"]["type"])
+	assert.Equal(t, create["Price"], update["Price"])
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/transport"
+	mcphttp "github.com/zmcp/odata-mcp/internal/transport/http"
+)
+
+// TestSessionInitializeState tests that each session keeps its own negotiated protocol version
+func TestSessionInitializeState(t *testing.T) {
+	server, ts := newStreamableTestServer(t)
+
+	first := postMCP(t, ts.URL, "", "application/json",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"first"}}}`)
+	first.Body.Close()
+	firstID := first.Header.Get(mcphttp.SessionIDHeader)
+
+	second := postMCP(t, ts.URL, "", "application/json",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"second"}}}`)
+	second.Body.Close()
+	secondID := second.Header.Get(mcphttp.SessionIDHeader)
+
+	require.NotEmpty(t, firstID)
+	require.NotEmpty(t, secondID)
+	assert.NotEqual(t, firstID, secondID)
+
+	notify := postMCP(t, ts.URL, firstID, "application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	notify.Body.Close()
+
+	firstSession, ok := server.GetSession(firstID)
+	require.True(t, ok)
+	assert.Equal(t, "2025-03-26", firstSession.ProtocolVersion)
+	assert.Equal(t, "first", firstSession.ClientInfo["name"])
+	assert.True(t, firstSession.Initialized)
+
+	secondSession, ok := server.GetSession(secondID)
+	require.True(t, ok)
+	assert.Equal(t, "2024-11-05", secondSession.ProtocolVersion)
+	assert.False(t, secondSession.Initialized)
+
+	// Terminating a session discards its state
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(mcphttp.SessionIDHeader, firstID)
+	del, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	del.Body.Close()
+
+	_, ok = server.GetSession(firstID)
+	assert.False(t, ok)
+	_, ok = server.GetSession(secondID)
+	assert.True(t, ok)
+}
+
+// TestSessionScopedNotification tests that a session notification only reaches that session's stream
+func TestSessionScopedNotification(t *testing.T) {
+	server, ts := newStreamableTestServer(t)
+
+	openStream := func(sessionID string) *bufio.Reader {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		t.Cleanup(cancel)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set(mcphttp.SessionIDHeader, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return bufio.NewReader(resp.Body)
+	}
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		resp := postMCP(t, ts.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+		resp.Body.Close()
+		ids = append(ids, resp.Header.Get(mcphttp.SessionIDHeader))
+	}
+	firstStream := openStream(ids[0])
+	secondStream := openStream(ids[1])
+
+	ctx := transport.WithSessionID(context.Background(), ids[1])
+	require.NoError(t, server.Notify(ctx, "notifications/message", map[string]interface{}{"data": "second only"}))
+	require.NoError(t, server.SendNotification("notifications/message", map[string]interface{}{"data": "everyone"}))
+
+	// The first session sees only the broadcast
+	assert.Contains(t, readSSEData(t, firstStream), "everyone")
+	assert.Contains(t, readSSEData(t, secondStream), "second only")
+	assert.Contains(t, readSSEData(t, secondStream), "everyone")
+}
+
+// TestSessionClientIsolation tests that session clients do not share CSRF tokens or session cookies
+func TestSessionClientIsolation(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		issued   int
+		received []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("X-CSRF-Token") == "Fetch" {
+			issued++
+			http.SetCookie(w, &http.Cookie{Name: "SAP_SESSIONID", Value: fmt.Sprintf("session-%d", issued)})
+			w.Header().Set("X-CSRF-Token", fmt.Sprintf("token-%d", issued))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		cookie := ""
+		if c, err := r.Cookie("SAP_SESSIONID"); err == nil {
+			cookie = c.Value
+		}
+		received = append(received, r.Header.Get("X-CSRF-Token")+"|"+cookie)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"d":{"ID":1}}`))
+	}))
+	defer server.Close()
+
+	shared := client.NewODataClient(server.URL, false)
+	first := shared.NewSession()
+	second := shared.NewSession()
+
+	ctx := context.Background()
+	_, err := first.CreateEntity(ctx, "Products", map[string]interface{}{"Name": "A"})
+	require.NoError(t, err)
+	_, err = second.CreateEntity(ctx, "Products", map[string]interface{}{"Name": "B"})
+	require.NoError(t, err)
+	_, err = first.CreateEntity(ctx, "Products", map[string]interface{}{"Name": "C"})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+	assert.Equal(t, "token-1|session-1", received[0])
+	assert.Equal(t, "token-2|session-2", received[1], "second session must not reuse the first session's token or cookie")
+	assert.Contains(t, received[2], "|session-1", "first session keeps its own server session")
+}
+
+// TestSessionlessRPCIsolation tests that /rpc requests without a session do
+// not share the CSRF token or session cookies of the bridge's client
+func TestSessionlessRPCIsolation(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		issued   int
+		received []string
+	)
+	odata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(passThroughMetadata))
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("X-CSRF-Token") == "Fetch" {
+			issued++
+			http.SetCookie(w, &http.Cookie{Name: "SAP_SESSIONID", Value: fmt.Sprintf("session-%d", issued)})
+			w.Header().Set("X-CSRF-Token", fmt.Sprintf("token-%d", issued))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		cookie := ""
+		if c, err := r.Cookie("SAP_SESSIONID"); err == nil {
+			cookie = c.Value
+		}
+		received = append(received, r.Header.Get("X-CSRF-Token")+"|"+cookie)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"d":{"ID":"1"}}`))
+	}))
+	defer odata.Close()
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: odata.URL + "/", NoPostfix: true})
+	require.NoError(t, err)
+	server := odataBridge.GetServer()
+	trans := mcphttp.NewSSE("", func(ctx context.Context, msg *transport.Message) (*transport.Message, error) {
+		return server.HandleMessage(ctx, msg)
+	})
+	ts := httptest.NewServer(trans)
+	defer ts.Close()
+
+	for _, id := range []string{"1", "2"} {
+		resp, err := http.Post(ts.URL+"/rpc", "application/json", strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"Products_create","arguments":{"ID":"`+id+`"}}}`))
+		require.NoError(t, err)
+		var msg transport.Message
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&msg))
+		resp.Body.Close()
+		require.Nil(t, msg.Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"token-1|session-1", "token-2|session-2"}, received,
+		"second caller must not reuse the first caller's token or cookie")
+}
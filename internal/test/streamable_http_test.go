@@ -0,0 +1,241 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/mcp"
+	"github.com/zmcp/odata-mcp/internal/transport"
+	mcphttp "github.com/zmcp/odata-mcp/internal/transport/http"
+)
+
+// newStreamableTestServer creates an MCP server with one echo tool behind a Streamable HTTP transport
+func newStreamableTestServer(t *testing.T) (*mcp.Server, *httptest.Server) {
+	server := mcp.NewServer("test-server", "1.0.0")
+	server.AddTool(&mcp.Tool{
+		Name:        "echo",
+		Description: "Echo the input",
+		InputSchema: map[string]interface{}{"type": "object"},
+	}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "echo", nil
+	})
+
+	trans := mcphttp.NewStreamableHTTP("", func(ctx context.Context, msg *transport.Message) (*transport.Message, error) {
+		return server.HandleMessage(ctx, msg)
+	})
+	server.SetTransport(trans)
+
+	ts := httptest.NewServer(trans)
+	t.Cleanup(ts.Close)
+	return server, ts
+}
+
+// postMCP sends a JSON-RPC message to the streamable endpoint
+func postMCP(t *testing.T, url, sessionID, accept, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if sessionID != "" {
+		req.Header.Set(mcphttp.SessionIDHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+// TestStreamableHTTPSessionLifecycle tests initialize, requests within a session and teardown
+func TestStreamableHTTPSessionLifecycle(t *testing.T) {
+	_, ts := newStreamableTestServer(t)
+
+	// Initialize creates a session and negotiates the requested protocol version
+	resp := postMCP(t, ts.URL, "", "application/json, text/event-stream",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(mcphttp.SessionIDHeader)
+	require.NotEmpty(t, sessionID)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	data := readSSEData(t, bufio.NewReader(resp.Body))
+	var initResp transport.Message
+	require.NoError(t, json.Unmarshal([]byte(data), &initResp))
+	var initResult map[string]interface{}
+	require.NoError(t, json.Unmarshal(initResp.Result, &initResult))
+	assert.Equal(t, "2025-03-26", initResult["protocolVersion"])
+
+	// Notifications are acknowledged with 202
+	notify := postMCP(t, ts.URL, sessionID, "application/json, text/event-stream",
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	notify.Body.Close()
+	assert.Equal(t, http.StatusAccepted, notify.StatusCode)
+
+	// Plain JSON responses are returned when the client does not accept SSE
+	list := postMCP(t, ts.URL, sessionID, "application/json", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	defer list.Body.Close()
+	require.Equal(t, http.StatusOK, list.StatusCode)
+	assert.Equal(t, "application/json", list.Header.Get("Content-Type"))
+	var listResp transport.Message
+	require.NoError(t, json.NewDecoder(list.Body).Decode(&listResp))
+	assert.Contains(t, string(listResp.Result), "echo")
+
+	// DELETE terminates the session
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(mcphttp.SessionIDHeader, sessionID)
+	del, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	del.Body.Close()
+	assert.Equal(t, http.StatusNoContent, del.StatusCode)
+
+	gone := postMCP(t, ts.URL, sessionID, "application/json", `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	gone.Body.Close()
+	assert.Equal(t, http.StatusNotFound, gone.StatusCode)
+}
+
+// TestStreamableHTTPRequiresSession tests that non-initialize requests need a session ID
+func TestStreamableHTTPRequiresSession(t *testing.T) {
+	_, ts := newStreamableTestServer(t)
+
+	resp := postMCP(t, ts.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodPut, ts.URL, nil)
+	put, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	put.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, put.StatusCode)
+}
+
+// TestStreamableHTTPServerNotifications tests that server notifications arrive on the GET stream
+func TestStreamableHTTPServerNotifications(t *testing.T) {
+	server, ts := newStreamableTestServer(t)
+
+	resp := postMCP(t, ts.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(mcphttp.SessionIDHeader)
+	require.NotEmpty(t, sessionID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(mcphttp.SessionIDHeader, sessionID)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	require.NoError(t, server.SendNotification("notifications/tools/list_changed", map[string]interface{}{}))
+
+	data := readSSEData(t, bufio.NewReader(stream.Body))
+	assert.Contains(t, data, "notifications/tools/list_changed")
+}
+
+// readSSEData reads the data field of the next SSE event
+func readSSEData(t *testing.T, reader *bufio.Reader) string {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "data: ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+		}
+	}
+}
+
+// TestStreamableHTTPSessionLimits tests idle expiry and the maximum number of sessions
+func TestStreamableHTTPSessionLimits(t *testing.T) {
+	server := mcp.NewServer("test-server", "1.0.0")
+	trans := mcphttp.NewStreamableHTTP("", func(ctx context.Context, msg *transport.Message) (*transport.Message, error) {
+		return server.HandleMessage(ctx, msg)
+	})
+	trans.SetSessionLimits(100*time.Millisecond, 2)
+	ts := httptest.NewServer(trans)
+	t.Cleanup(ts.Close)
+
+	initialize := func() *http.Response {
+		resp := postMCP(t, ts.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+		resp.Body.Close()
+		return resp
+	}
+
+	first := initialize().Header.Get(mcphttp.SessionIDHeader)
+	require.NotEmpty(t, first)
+	require.NotEmpty(t, initialize().Header.Get(mcphttp.SessionIDHeader))
+	assert.Equal(t, http.StatusServiceUnavailable, initialize().StatusCode)
+
+	// Idle sessions expire and free their slot
+	time.Sleep(150 * time.Millisecond)
+	gone := postMCP(t, ts.URL, first, "application/json", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	gone.Body.Close()
+	assert.Equal(t, http.StatusNotFound, gone.StatusCode)
+	assert.Equal(t, http.StatusOK, initialize().StatusCode)
+}
+
+// TestStreamableHTTPOrigin tests that browser requests from foreign origins are rejected
+func TestStreamableHTTPOrigin(t *testing.T) {
+	server := mcp.NewServer("test-server", "1.0.0")
+	trans := mcphttp.NewStreamableHTTP("", func(ctx context.Context, msg *transport.Message) (*transport.Message, error) {
+		return server.HandleMessage(ctx, msg)
+	})
+	trans.SetAllowedOrigins([]string{"https://app.example.com"})
+	ts := httptest.NewServer(trans)
+	t.Cleanup(ts.Close)
+
+	for origin, status := range map[string]int{
+		"":                        http.StatusOK,
+		"http://localhost:3000":   http.StatusOK,
+		"http://127.0.0.1:8080":   http.StatusOK,
+		"https://app.example.com": http.StatusOK,
+		"http://attacker.example": http.StatusForbidden,
+		"null":                    http.StatusForbidden,
+	} {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode, origin)
+	}
+}
+
+// TestStreamableHTTPProtocolVersion tests that requests after initialize must name a supported protocol version
+func TestStreamableHTTPProtocolVersion(t *testing.T) {
+	_, ts := newStreamableTestServer(t)
+
+	resp := postMCP(t, ts.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(mcphttp.SessionIDHeader)
+	require.NotEmpty(t, sessionID)
+
+	for version, status := range map[string]int{
+		"":           http.StatusOK,
+		"2025-06-18": http.StatusOK,
+		"2024-11-05": http.StatusOK,
+		"1999-01-01": http.StatusBadRequest,
+	} {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(mcphttp.SessionIDHeader, sessionID)
+		if version != "" {
+			req.Header.Set(mcphttp.ProtocolVersionHeader, version)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode, version)
+	}
+}
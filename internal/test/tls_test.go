@@ -0,0 +1,259 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/client"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// testPKI holds a private CA with a server and a client certificate written as
+// PEM files, and the client certificate with the CA as password-protected PKCS#12
+type testPKI struct {
+	caPool     *x509.CertPool
+	caFile     string
+	serverCert tls.Certificate
+	clientCert string
+	clientKey  string
+	clientP12  string
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, cn string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	pki := &testPKI{
+		caPool:     x509.NewCertPool(),
+		caFile:     filepath.Join(dir, "ca.pem"),
+		clientCert: filepath.Join(dir, "client.pem"),
+		clientKey:  filepath.Join(dir, "client-key.pem"),
+		clientP12:  filepath.Join(dir, "client.p12"),
+	}
+	pki.caPool.AddCert(caCert)
+	require.NoError(t, os.WriteFile(pki.caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+
+	serverPEM, serverKeyPEM := issue(2, "localhost", x509.ExtKeyUsageServerAuth)
+	pki.serverCert, err = tls.X509KeyPair(serverPEM, serverKeyPEM)
+	require.NoError(t, err)
+
+	clientPEM, clientKeyPEM := issue(3, "odata-client", x509.ExtKeyUsageClientAuth)
+	require.NoError(t, os.WriteFile(pki.clientCert, clientPEM, 0600))
+	require.NoError(t, os.WriteFile(pki.clientKey, clientKeyPEM, 0600))
+
+	clientPair, err := tls.X509KeyPair(clientPEM, clientKeyPEM)
+	require.NoError(t, err)
+	clientLeaf, err := x509.ParseCertificate(clientPair.Certificate[0])
+	require.NoError(t, err)
+	p12, err := pkcs12.Modern.Encode(clientPair.PrivateKey, clientLeaf, []*x509.Certificate{caCert}, "secret")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(pki.clientP12, p12, 0600))
+
+	return pki
+}
+
+// newTLSService starts an OData service over TLS that reports the client certificate subject
+func newTLSService(t *testing.T, pki *testPKI, clientAuth tls.ClientAuthType, maxVersion uint16) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(passThroughMetadata))
+			return
+		}
+		subject := ""
+		if len(r.TLS.PeerCertificates) > 0 {
+			subject = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"ID":"` + subject + `"}]}}`))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientAuth:   clientAuth,
+		ClientCAs:    pki.caPool,
+		MaxVersion:   maxVersion,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTLSClient(url string, opts client.TLSOptions) (*client.ODataClient, error) {
+	tlsConfig, err := client.BuildTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	odataClient := client.NewODataClient(url, false)
+	odataClient.SetTLSConfig(tlsConfig)
+	return odataClient, nil
+}
+
+// TestMutualTLS tests that the client certificate is presented to a service signed by a private CA
+func TestMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	server := newTLSService(t, pki, tls.RequireAndVerifyClientCert, 0)
+	ctx := context.Background()
+
+	odataClient, err := newTLSClient(server.URL, client.TLSOptions{
+		CAFile:   pki.caFile,
+		CertFile: pki.clientCert,
+		KeyFile:  pki.clientKey,
+	})
+	require.NoError(t, err)
+	resp, err := odataClient.GetEntitySet(ctx, "Products", nil)
+	require.NoError(t, err)
+	data, err := json.Marshal(resp.Value)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "odata-client")
+
+	// Without a client certificate the handshake fails
+	odataClient, err = newTLSClient(server.URL, client.TLSOptions{CAFile: pki.caFile})
+	require.NoError(t, err)
+	_, err = odataClient.GetEntitySet(ctx, "Products", nil)
+	assert.Error(t, err)
+}
+
+// TestMutualTLSPKCS12 tests that a PKCS#12 client certificate is presented with its chain
+func TestMutualTLSPKCS12(t *testing.T) {
+	pki := newTestPKI(t)
+	server := newTLSService(t, pki, tls.RequireAndVerifyClientCert, 0)
+
+	tlsConfig, err := client.BuildTLSConfig(client.TLSOptions{
+		CAFile:       pki.caFile,
+		CertFile:     pki.clientP12,
+		CertPassword: "secret",
+	})
+	require.NoError(t, err)
+	require.Len(t, tlsConfig.Certificates, 1)
+	assert.Len(t, tlsConfig.Certificates[0].Certificate, 2)
+	assert.Equal(t, "odata-client", tlsConfig.Certificates[0].Leaf.Subject.CommonName)
+
+	odataClient := client.NewODataClient(server.URL, false)
+	odataClient.SetTLSConfig(tlsConfig)
+	resp, err := odataClient.GetEntitySet(context.Background(), "Products", nil)
+	require.NoError(t, err)
+	data, err := json.Marshal(resp.Value)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "odata-client")
+}
+
+// TestTLSServerVerification tests CA trust, insecure-skip-verify and the minimum TLS version
+func TestTLSServerVerification(t *testing.T) {
+	pki := newTestPKI(t)
+	server := newTLSService(t, pki, tls.NoClientCert, tls.VersionTLS12)
+	ctx := context.Background()
+
+	// The private CA is unknown without a bundle
+	_, err := client.NewODataClient(server.URL, false).GetEntitySet(ctx, "Products", nil)
+	require.Error(t, err)
+
+	odataClient, err := newTLSClient(server.URL, client.TLSOptions{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	_, err = odataClient.GetEntitySet(ctx, "Products", nil)
+	assert.NoError(t, err)
+
+	// The server only speaks TLS 1.2
+	odataClient, err = newTLSClient(server.URL, client.TLSOptions{CAFile: pki.caFile, MinVersion: "1.3"})
+	require.NoError(t, err)
+	_, err = odataClient.GetEntitySet(ctx, "Products", nil)
+	assert.Error(t, err)
+}
+
+// TestTLSOptionErrors tests that invalid TLS options are reported
+func TestTLSOptionErrors(t *testing.T) {
+	pki := newTestPKI(t)
+
+	_, err := client.BuildTLSConfig(client.TLSOptions{MinVersion: "1.4"})
+	assert.ErrorContains(t, err, "unsupported TLS version")
+
+	_, err = client.BuildTLSConfig(client.TLSOptions{CertFile: pki.clientP12, CertPassword: "wrong"})
+	assert.ErrorContains(t, err, "failed to load PKCS#12 client certificate")
+
+	_, err = client.BuildTLSConfig(client.TLSOptions{CertFile: pki.clientP12, KeyFile: pki.clientKey, CertPassword: "secret"})
+	assert.ErrorContains(t, err, "PKCS#12 client certificate, which contains the key")
+
+	_, err = client.BuildTLSConfig(client.TLSOptions{KeyFile: pki.clientKey})
+	assert.ErrorContains(t, err, "without a client certificate")
+
+	_, err = client.BuildTLSConfig(client.TLSOptions{CAFile: pki.clientKey})
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+// TestTLSTraceInfo tests that the TLS settings are reported in trace mode
+func TestTLSTraceInfo(t *testing.T) {
+	pki := newTestPKI(t)
+	server := newTLSService(t, pki, tls.RequireAndVerifyClientCert, 0)
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{
+		ServiceURL:    server.URL + "/",
+		CAFile:        pki.caFile,
+		ClientCert:    pki.clientCert,
+		ClientKey:     pki.clientKey,
+		TLSMinVersion: "1.2",
+	})
+	require.NoError(t, err)
+
+	info, err := odataBridge.GetTraceInfo()
+	require.NoError(t, err)
+	require.NotNil(t, info.TLS)
+	assert.Equal(t, pki.caFile, info.TLS.CAFile)
+	assert.Equal(t, "CN=odata-client", info.TLS.ClientCertificate)
+	assert.NotEmpty(t, info.TLS.ClientCertExpires)
+	assert.Equal(t, "TLS 1.2", info.TLS.MinVersion)
+	assert.False(t, info.TLS.InsecureSkipVerify)
+}
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const annotatedV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:Reference Uri="https://oasis-tcs.github.io/odata-vocabularies/vocabularies/Org.OData.Core.V1.xml">
+    <edmx:Include Namespace="Org.OData.Core.V1" Alias="Core"/>
+  </edmx:Reference>
+  <edmx:Reference Uri="https://oasis-tcs.github.io/odata-vocabularies/vocabularies/Org.OData.Capabilities.V1.xml">
+    <edmx:Include Namespace="Org.OData.Capabilities.V1" Alias="Capabilities"/>
+  </edmx:Reference>
+  <edmx:DataServices>
+    <Schema Namespace="Audit" Alias="A" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Entry">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Tenant" Type="Edm.String"/>
+        <Property Name="Message" Type="Edm.String">
+          <Annotation Term="Core.Description" String="Log message"/>
+        </Property>
+        <Property Name="CreatedAt" Type="Edm.DateTimeOffset">
+          <Annotation Term="Core.Computed"/>
+        </Property>
+        <Property Name="Source" Type="Edm.String"/>
+      </EntityType>
+      <EntityType Name="Setting">
+        <Key><PropertyRef Name="Name"/></Key>
+        <Property Name="Name" Type="Edm.String" Nullable="false"/>
+        <Property Name="Value" Type="Edm.String"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Entries" EntityType="Audit.Entry">
+          <Annotation Term="Capabilities.DeleteRestrictions">
+            <Record><PropertyValue Property="Deletable" Bool="false"/></Record>
+          </Annotation>
+          <Annotation Term="Capabilities.CountRestrictions">
+            <Record><PropertyValue Property="Countable" Bool="false"/></Record>
+          </Annotation>
+        </EntitySet>
+        <EntitySet Name="Settings" EntityType="Audit.Setting"/>
+      </EntityContainer>
+      <Annotations Target="A.Entry/Source">
+        <Annotation Term="Org.OData.Core.V1.Immutable" Bool="true"/>
+      </Annotations>
+      <Annotations Target="Audit.Container/Entries">
+        <Annotation Term="Core.Description" String="Audit log entries"/>
+        <Annotation Term="Capabilities.TopSupported" Bool="false"/>
+        <Annotation Term="Capabilities.FilterRestrictions">
+          <Record>
+            <PropertyValue Property="RequiresFilter" Bool="true"/>
+            <PropertyValue Property="RequiredProperties">
+              <Collection><PropertyPath>Tenant</PropertyPath></Collection>
+            </PropertyValue>
+            <PropertyValue Property="NonFilterableProperties">
+              <Collection><PropertyPath>Message</PropertyPath></Collection>
+            </PropertyValue>
+          </Record>
+        </Annotation>
+      </Annotations>
+      <Annotations Target="Audit.Container/Settings">
+        <Annotation Term="Capabilities.InsertRestrictions">
+          <Record><PropertyValue Property="Insertable" Bool="false"/></Record>
+        </Annotation>
+        <Annotation Term="Capabilities.UpdateRestrictions">
+          <Record><PropertyValue Property="Updatable"><Bool>false</Bool></PropertyValue></Record>
+        </Annotation>
+        <Annotation Term="Capabilities.SearchRestrictions">
+          <Record><PropertyValue Property="Searchable" Bool="false"/></Record>
+        </Annotation>
+        <Annotation Term="Capabilities.FilterRestrictions">
+          <Record><PropertyValue Property="Filterable" Bool="false"/></Record>
+        </Annotation>
+      </Annotations>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseV4Annotations tests inline and targeted Capabilities and Core annotations
+func TestParseV4Annotations(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(annotatedV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	entries := meta.EntitySets["Entries"]
+	assert.False(t, entries.Deletable)
+	assert.False(t, entries.Countable)
+	assert.False(t, entries.TopSupported)
+	assert.True(t, entries.RequiresFilter)
+	assert.Equal(t, []string{"Tenant"}, entries.RequiredFilterProperties)
+	assert.Equal(t, []string{"Message"}, entries.NonFilterableProperties)
+	require.NotNil(t, entries.Description)
+	assert.Equal(t, "Audit log entries", *entries.Description)
+
+	settings := meta.EntitySets["Settings"]
+	assert.False(t, settings.Creatable)
+	assert.False(t, settings.Updatable)
+	assert.False(t, settings.Searchable)
+	assert.False(t, settings.Filterable)
+	assert.True(t, settings.Deletable)
+
+	props := meta.EntityTypes["Entry"].Properties
+	assert.Equal(t, "Log message", *props[2].Description)
+	assert.False(t, props[3].Creatable)
+	assert.False(t, props[3].Updatable)
+	assert.True(t, props[4].Creatable)
+	assert.False(t, props[4].Updatable)
+}
+
+// TestV4AnnotationToolGeneration tests that Capabilities and Core annotations gate tools and shape their schemas
+func TestV4AnnotationToolGeneration(t *testing.T) {
+	schemas := toolSchemas(t, annotatedV4Metadata)
+
+	assert.NotContains(t, schemas, "Entries_delete")
+	assert.NotContains(t, schemas, "Entries_count")
+	assert.NotContains(t, schemas["Entries_filter"], "$top")
+	assert.NotContains(t, schemas["Entries_filter"], "$count")
+	filter := schemas["Entries_filter"]["$filter"]["description"].(string)
+	assert.Contains(t, filter, "Filterable properties: ID, Tenant, CreatedAt, Source")
+	assert.Contains(t, filter, "Required filter properties: Tenant")
+
+	assert.Equal(t, "Log message", schemas["Entries_create"]["Message"]["description"])
+	assert.NotContains(t, schemas["Entries_create"], "CreatedAt")
+	assert.Contains(t, schemas["Entries_create"], "Source")
+	assert.NotContains(t, schemas["Entries_update"], "CreatedAt")
+	assert.NotContains(t, schemas["Entries_update"], "Source")
+
+	assert.NotContains(t, schemas, "Settings_create")
+	assert.NotContains(t, schemas, "Settings_update")
+	assert.NotContains(t, schemas, "Settings_search")
+	assert.Contains(t, schemas, "Settings_delete")
+	assert.NotContains(t, schemas["Settings_filter"], "$filter")
+	assert.NotContains(t, schemas["Settings_count"], "$filter")
+}
+
+// TestV4RequiresFilterEnforced tests that FilterRestrictions are enforced before the service is called
+func TestV4RequiresFilterEnforced(t *testing.T) {
+	server, requests := newTestBridge(t, annotatedV4Metadata, &config.Config{})
+
+	resp := invokeTool(t, server, "Entries_filter", map[string]interface{}{})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "requires a $filter")
+
+	resp = invokeTool(t, server, "Entries_filter", map[string]interface{}{"$filter": "ID gt 5"})
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, "Tenant")
+	assert.Empty(t, requests())
+
+	resp = invokeTool(t, server, "Entries_filter", map[string]interface{}{"$filter": "Tenant eq 'acme'"})
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, []string{"GET /Entries"}, requests())
+}
@@ -0,0 +1,149 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/bridge"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const boundOperationsMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Sales" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Status" Type="Edm.String"/>
+      </EntityType>
+      <Action Name="Approve" IsBound="true">
+        <Parameter Name="order" Type="Sales.Order" Nullable="false"/>
+        <Parameter Name="Comment" Type="Edm.String" Nullable="false"/>
+        <ReturnType Type="Sales.Order"/>
+      </Action>
+      <Function Name="Overdue" IsBound="true">
+        <Parameter Name="orders" Type="Collection(Sales.Order)" Nullable="false"/>
+        <Parameter Name="Days" Type="Edm.Int32" Nullable="false"/>
+        <Parameter Name="Region" Type="Edm.String"/>
+        <ReturnType Type="Collection(Sales.Order)"/>
+      </Function>
+      <Function Name="TopOrders">
+        <ReturnType Type="Collection(Sales.Order)"/>
+      </Function>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Orders" EntityType="Sales.Order"/>
+        <FunctionImport Name="TopOrders" Function="Sales.TopOrders"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseBoundOperationsV4 tests that bound functions and actions reach the metadata model
+func TestParseBoundOperationsV4(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(boundOperationsMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	require.Len(t, meta.BoundOperations, 2)
+	approve := meta.BoundOperations[1]
+	assert.Equal(t, "Sales.Approve", approve.QualifiedName)
+	assert.Equal(t, "Order", approve.BindingType)
+	assert.True(t, approve.IsAction)
+	assert.Equal(t, "POST", approve.HTTPMethod)
+	require.Len(t, approve.Parameters, 1, "the binding parameter is not a tool parameter")
+	assert.Equal(t, "Comment", approve.Parameters[0].Name)
+
+	overdue := meta.BoundOperations[0]
+	assert.Equal(t, "Collection(Order)", overdue.BindingType)
+	assert.Equal(t, "GET", overdue.HTTPMethod)
+
+	assert.Contains(t, meta.FunctionImports, "TopOrders")
+	assert.NotContains(t, meta.FunctionImports, "Approve")
+}
+
+// TestBoundOperationTools tests that bound operations take the bound entity's key and call the qualified URL
+func TestBoundOperationTools(t *testing.T) {
+	schemas := toolSchemas(t, boundOperationsMetadata)
+
+	approve := schemas["Orders_Approve"]
+	require.NotNil(t, approve)
+	assert.Contains(t, approve, "ID")
+	assert.Contains(t, approve, "Comment")
+
+	overdue := schemas["Orders_Overdue"]
+	require.NotNil(t, overdue)
+	assert.NotContains(t, overdue, "ID", "collection-bound functions take no key")
+	assert.Contains(t, overdue, "Days")
+
+	server, requests := newTestBridge(t, boundOperationsMetadata, &config.Config{})
+
+	request := dryRunRequest(t, toolText(t, server, "Orders_Approve", map[string]interface{}{
+		"$dry_run": true,
+		"ID":       1,
+		"Comment":  "ok",
+	}))
+	assert.Equal(t, "POST", request["method"])
+	assert.Contains(t, request["url"], "/Orders(1)/Sales.Approve")
+	assert.Equal(t, map[string]interface{}{"Comment": "ok"}, request["body"])
+
+	toolText(t, server, "Orders_Overdue", map[string]interface{}{"Days": 30})
+	assert.Equal(t, []string{"GET /Orders/Sales.Overdue(Days=30)"}, requests())
+}
+
+// TestBoundActionsReadOnly tests that read-only mode hides bound actions but keeps bound functions
+func TestBoundActionsReadOnly(t *testing.T) {
+	server, _ := newTestBridge(t, boundOperationsMetadata, &config.Config{ReadOnly: true})
+
+	resp := invokeTool(t, server, "Orders_Approve", map[string]interface{}{"ID": 1, "Comment": "ok"})
+	require.NotNil(t, resp.Error)
+
+	resp = invokeTool(t, server, "Orders_Overdue", map[string]interface{}{"Days": 30})
+	assert.Nil(t, resp.Error)
+}
+
+// TestBoundFunctionParameters tests that inline string parameters cannot break the URL path
+func TestBoundFunctionParameters(t *testing.T) {
+	server, requests := newTestBridge(t, boundOperationsMetadata, &config.Config{})
+
+	toolText(t, server, "Orders_Overdue", map[string]interface{}{"Days": 30, "Region": "O'Neil?x#y/z&w"})
+	assert.Equal(t, []string{"GET /Orders/Sales.Overdue(Days=30,Region='O''Neil?x#y/z&w')"}, requests())
+}
+
+// TestBoundOperationHiddenFields tests that hidden fields are removed from bound operation results
+func TestBoundOperationHiddenFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/$metadata"):
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(boundOperationsMetadata))
+		case strings.Contains(r.URL.Path, "Sales.Overdue"):
+			w.Write([]byte(`{"value":[{"ID":1,"Status":"late","Cost":"0.10"}]}`))
+		default:
+			w.Write([]byte(`{"ID":1,"Status":"approved","Cost":"0.10"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	odataBridge, err := bridge.NewODataMCPBridge(&config.Config{
+		ServiceURL:      server.URL + "/",
+		NoPostfix:       true,
+		EntityOverrides: map[string]config.EntityOverride{"Orders": {HiddenFields: []string{"Status"}}},
+	})
+	require.NoError(t, err)
+
+	for tool, args := range map[string]map[string]interface{}{
+		"Orders_Overdue": {"Days": 30},
+		"Orders_Approve": {"ID": 1, "Comment": "ok"},
+	} {
+		resp := invokeTool(t, odataBridge.GetServer(), tool, args)
+		require.Nil(t, resp.Error, tool)
+		assert.Contains(t, string(resp.Result), "Cost", tool)
+		assert.NotContains(t, string(resp.Result), "Status", tool)
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const singletonsMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Org" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Person">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Name" Type="Edm.String" Nullable="false"/>
+        <Property Name="Phone" Type="Edm.String"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="People" EntityType="Org.Person"/>
+        <Singleton Name="Me" Type="Org.Person"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseSingletonsV4 tests that singletons reach the metadata model
+func TestParseSingletonsV4(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(singletonsMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	me := meta.Singletons["Me"]
+	require.NotNil(t, me)
+	assert.Equal(t, "Person", me.EntityType)
+	assert.True(t, me.Updatable)
+}
+
+// TestSingletonTools tests that singleton tools take no key and address the singleton by name
+func TestSingletonTools(t *testing.T) {
+	schemas := toolSchemas(t, singletonsMetadata)
+
+	get := schemas["Me_get"]
+	require.NotNil(t, get)
+	assert.Contains(t, get, "$select")
+	assert.Contains(t, get, "$expand")
+	assert.NotContains(t, get, "ID")
+
+	update := schemas["Me_update"]
+	require.NotNil(t, update)
+	assert.Contains(t, update, "Phone")
+	assert.NotContains(t, update, "ID")
+	assert.Equal(t, "PATCH", update["_method"]["default"])
+
+	assert.NotContains(t, schemas, "Me_create")
+	assert.NotContains(t, schemas, "Me_delete")
+	assert.NotContains(t, schemas, "Me_filter")
+
+	server, requests := newTestBridge(t, singletonsMetadata, &config.Config{})
+
+	toolText(t, server, "Me_get", map[string]interface{}{"$select": "Name"})
+	assert.Equal(t, []string{"GET /Me"}, requests())
+
+	request := dryRunRequest(t, toolText(t, server, "Me_update", map[string]interface{}{
+		"$dry_run": true,
+		"Phone":    "555-0100",
+	}))
+	assert.Equal(t, "PATCH", request["method"])
+	assert.Contains(t, request["url"], "/Me")
+	assert.Equal(t, map[string]interface{}{"Phone": "555-0100"}, request["body"])
+}
+
+// TestSingletonReadOnly tests that read-only mode hides singleton updates
+func TestSingletonReadOnly(t *testing.T) {
+	server, _ := newTestBridge(t, singletonsMetadata, &config.Config{ReadOnly: true})
+
+	resp := invokeTool(t, server, "Me_update", map[string]interface{}{"Phone": "555-0100"})
+	require.NotNil(t, resp.Error)
+
+	resp = invokeTool(t, server, "Me_get", map[string]interface{}{})
+	assert.Nil(t, resp.Error)
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmcp/odata-mcp/internal/config"
+	"github.com/zmcp/odata-mcp/internal/metadata"
+)
+
+const structuredTypesMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Shop" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EnumType Name="Color">
+        <Member Name="Red" Value="0"/>
+        <Member Name="Green" Value="1"/>
+      </EnumType>
+      <EnumType Name="Access" IsFlags="true">
+        <Member Name="Read" Value="1"/>
+        <Member Name="Write" Value="2"/>
+        <Member Name="Admin" Value="4"/>
+      </EnumType>
+      <ComplexType Name="Location">
+        <Property Name="City" Type="Edm.String" Nullable="false" MaxLength="40"/>
+      </ComplexType>
+      <ComplexType Name="Address" BaseType="Shop.Location">
+        <Property Name="Street" Type="Edm.String"/>
+        <Property Name="Kind" Type="Shop.Color"/>
+      </ComplexType>
+      <EntityType Name="Customer">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Favorite" Type="Shop.Color"/>
+        <Property Name="Rights" Type="Shop.Access" Nullable="false"/>
+        <Property Name="Home" Type="Shop.Address"/>
+        <Property Name="Addresses" Type="Collection(Shop.Address)"/>
+        <Property Name="Tags" Type="Collection(Edm.String)" MaxLength="10"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Customers" EntityType="Shop.Customer"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// TestParseStructuredTypesV4 tests that complex and enum types reach the metadata model
+func TestParseStructuredTypesV4(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(structuredTypesMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	address := meta.ComplexTypes["Address"]
+	require.NotNil(t, address)
+	names := make([]string, 0)
+	for _, prop := range address.Properties {
+		names = append(names, prop.Name)
+	}
+	assert.Equal(t, []string{"City", "Street", "Kind"}, names, "base type properties come first")
+
+	access := meta.EnumTypes["Access"]
+	require.NotNil(t, access)
+	assert.True(t, access.IsFlags)
+	assert.Equal(t, "Edm.Int32", access.UnderlyingType)
+	assert.Equal(t, int64(4), access.Members[2].Value)
+}
+
+// TestStructuredTypeSchemas tests complex types as objects, enums and collections as arrays
+func TestStructuredTypeSchemas(t *testing.T) {
+	create := toolSchemas(t, structuredTypesMetadata)["Customers_create"]
+	require.NotNil(t, create)
+
+	assert.Equal(t, []interface{}{"string", "null"}, create["Favorite"]["type"])
+	assert.Equal(t, []interface{}{"Red", "Green"}, create["Favorite"]["enum"])
+
+	assert.Equal(t, "array", create["Rights"]["type"])
+	assert.Equal(t, []interface{}{"Read", "Write", "Admin"}, create["Rights"]["items"].(map[string]interface{})["enum"])
+
+	home := create["Home"]
+	assert.Equal(t, []interface{}{"object", "null"}, home["type"])
+	homeProps := home["properties"].(map[string]interface{})
+	assert.Equal(t, float64(40), homeProps["City"].(map[string]interface{})["maxLength"])
+	assert.Equal(t, []interface{}{"Red", "Green"}, homeProps["Kind"].(map[string]interface{})["enum"])
+	assert.Equal(t, []interface{}{"City"}, home["required"])
+
+	addresses := create["Addresses"]
+	assert.Equal(t, []interface{}{"array", "null"}, addresses["type"])
+	assert.Contains(t, addresses["items"].(map[string]interface{})["properties"], "Street")
+
+	tags := create["Tags"]["items"].(map[string]interface{})
+	assert.Equal(t, "string", tags["type"])
+	assert.Equal(t, float64(10), tags["maxLength"])
+}
+
+// TestStructuredTypePayload tests that enums, flags and nested values are serialized for the service
+func TestStructuredTypePayload(t *testing.T) {
+	server, _ := newTestBridge(t, structuredTypesMetadata, &config.Config{})
+
+	request := dryRunRequest(t, toolText(t, server, "Customers_create", map[string]interface{}{
+		"$dry_run":  true,
+		"Favorite":  float64(1),
+		"Rights":    []interface{}{"Read", "Admin"},
+		"Home":      map[string]interface{}{"City": "Berlin", "Kind": "Red"},
+		"Addresses": []interface{}{map[string]interface{}{"City": "Paris", "Kind": float64(1)}},
+		"Tags":      []interface{}{"vip"},
+	}))
+
+	assert.Equal(t, map[string]interface{}{
+		"Favorite":  "Green",
+		"Rights":    "Read,Admin",
+		"Home":      map[string]interface{}{"City": "Berlin", "Kind": "Red"},
+		"Addresses": []interface{}{map[string]interface{}{"City": "Paris", "Kind": "Green"}},
+		"Tags":      []interface{}{"vip"},
+	}, request["body"])
+}
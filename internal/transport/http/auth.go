@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// passThroughAuth extracts end-user credentials from incoming HTTP requests
+// so they can be forwarded to the OData service instead of the configured identity
+type passThroughAuth struct {
+	enabled bool
+	cookies []string
+}
+
+// credentials returns the pass-through credentials of r, or nil if it carries none
+func (p *passThroughAuth) credentials(r *http.Request) *transport.Credentials {
+	if !p.enabled {
+		return nil
+	}
+
+	creds := &transport.Credentials{
+		Authorization: r.Header.Get("Authorization"),
+		Cookies:       make(map[string]string),
+	}
+	for _, name := range p.cookies {
+		if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+			creds.Cookies[name] = cookie.Value
+		}
+	}
+
+	if creds.Authorization == "" && len(creds.Cookies) == 0 {
+		return nil
+	}
+	return creds
+}
+
+// withCredentials attaches the pass-through credentials of r to ctx. It returns
+// false after answering 401 when pass-through is enabled and r has no credentials.
+func (p *passThroughAuth) withCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	if !p.enabled {
+		return ctx, true
+	}
+
+	creds := p.credentials(r)
+	if creds == nil {
+		writeUnauthorized(w)
+		return ctx, false
+	}
+	return transport.WithCredentials(ctx, creds), true
+}
+
+// writeUnauthorized rejects a request that lacks end-user credentials
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="odata-mcp"`)
+	http.Error(w, "Missing end-user credentials", http.StatusUnauthorized)
+}
@@ -17,6 +17,8 @@ type SSETransport struct {
 	server   *http.Server
 	handler  transport.Handler
 	clients  map[string]*sseClient
+	onClose  []func(sessionID string)
+	auth     passThroughAuth
 	mu       sync.RWMutex
 	messages chan *clientMessage
 }
@@ -27,11 +29,13 @@ type sseClient struct {
 	done     chan struct{}
 	writer   http.ResponseWriter
 	flusher  http.Flusher
+	creds    *transport.Credentials
 }
 
 type clientMessage struct {
 	clientID string
 	message  *transport.Message
+	creds    *transport.Credentials
 }
 
 // NewSSE creates a new SSE transport
@@ -44,15 +48,22 @@ func NewSSE(addr string, handler transport.Handler) *SSETransport {
 	}
 }
 
+// SetPassThroughAuth makes requests forward the caller's Authorization header
+// and the named cookies to the OData service. Requests without them are rejected.
+// Credentials given when opening /sse apply to the whole session unless an
+// /rpc request carries its own.
+func (t *SSETransport) SetPassThroughAuth(cookieNames []string) {
+	t.auth = passThroughAuth{enabled: true, cookies: cookieNames}
+}
+
 // Start initializes the HTTP server and begins listening
 func (t *SSETransport) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 	
-	// SSE endpoint for bidirectional communication
-	mux.HandleFunc("/sse", t.handleSSE)
-	
-	// Regular HTTP endpoint for request-response
-	mux.HandleFunc("/rpc", t.handleRPC)
+	// SSE endpoint for bidirectional communication and regular HTTP
+	// endpoint for request-response
+	mux.Handle("/sse", t)
+	mux.Handle("/rpc", t)
 	
 	// Health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +90,18 @@ func (t *SSETransport) Start(ctx context.Context) error {
 	return t.Close()
 }
 
+// ServeHTTP dispatches requests to the /sse and /rpc endpoints
+func (t *SSETransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/sse":
+		t.handleSSE(w, r)
+	case "/rpc":
+		t.handleRPC(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
 // handleSSE handles SSE connections
 func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
 	// Check if the request accepts SSE
@@ -94,19 +117,34 @@ func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Sessions of a pass-through transport always belong to an end user
+	creds := t.auth.credentials(r)
+	if t.auth.enabled && creds == nil {
+		writeUnauthorized(w)
+		return
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
+	// Each SSE connection is its own session, so the ID must not be guessable
+	clientID, err := newSessionID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// Create client
 	client := &sseClient{
-		id:      fmt.Sprintf("client-%d", time.Now().UnixNano()),
+		id:      clientID,
 		events:  make(chan []byte, 10),
 		done:    make(chan struct{}),
 		writer:  w,
 		flusher: flusher,
+		creds:   creds,
 	}
 
 	// Register client
@@ -115,15 +153,21 @@ func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
 	t.mu.Unlock()
 
 	// Send connection event
-	t.sendEvent(client, "connected", map[string]string{"clientId": client.id})
+	t.sendEvent(client, "connected", map[string]string{
+		"clientId": client.id,
+		"endpoint": "/rpc?sessionId=" + client.id,
+	})
 
 	// Clean up on disconnect
 	defer func() {
 		t.mu.Lock()
 		delete(t.clients, client.id)
+		callbacks := t.onClose
 		t.mu.Unlock()
-		close(client.events)
 		close(client.done)
+		for _, fn := range callbacks {
+			fn(client.id)
+		}
 	}()
 
 	// Handle incoming messages from query parameters or POST body
@@ -133,6 +177,7 @@ func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
 			t.messages <- &clientMessage{
 				clientID: client.id,
 				message:  &msg,
+				creds:    creds,
 			}
 		}
 	}
@@ -164,8 +209,34 @@ func (t *SSETransport) handleRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Process the message
+	// Requests that name an SSE session run in that session's context, any
+	// other request gets OData session state of its own
 	ctx := r.Context()
+	creds := t.auth.credentials(r)
+	if sessionID := rpcSessionID(r); sessionID == "" {
+		ctx = transport.WithIsolation(ctx)
+	} else {
+		t.mu.RLock()
+		client, exists := t.clients[sessionID]
+		t.mu.RUnlock()
+		if !exists {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		ctx = transport.WithSessionID(ctx, sessionID)
+		if creds == nil {
+			creds = client.creds
+		}
+	}
+
+	if creds != nil {
+		ctx = transport.WithCredentials(ctx, creds)
+	} else if t.auth.enabled {
+		writeUnauthorized(w)
+		return
+	}
+
+	// Process the message
 	response, err := t.handler(ctx, &msg)
 	if err != nil {
 		response = &transport.Message{
@@ -190,7 +261,11 @@ func (t *SSETransport) processMessages(ctx context.Context) {
 			return
 		case cm := <-t.messages:
 			if cm.message.Method != "" && t.handler != nil {
-				response, err := t.handler(ctx, cm.message)
+				msgCtx := transport.WithSessionID(ctx, cm.clientID)
+				if cm.creds != nil {
+					msgCtx = transport.WithCredentials(msgCtx, cm.creds)
+				}
+				response, err := t.handler(msgCtx, cm.message)
 				if err != nil {
 					response = &transport.Message{
 						JSONRPC: "2.0",
@@ -220,6 +295,14 @@ func (t *SSETransport) processMessages(ctx context.Context) {
 	}
 }
 
+// rpcSessionID returns the session an /rpc request belongs to, if any
+func rpcSessionID(r *http.Request) string {
+	if id := r.Header.Get(SessionIDHeader); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("sessionId")
+}
+
 // sendEvent sends an event to a specific client
 func (t *SSETransport) sendEvent(client *sseClient, eventType string, data interface{}) {
 	event := map[string]interface{}{
@@ -267,6 +350,36 @@ func (t *SSETransport) WriteMessage(msg *transport.Message) error {
 	return t.BroadcastMessage(msg)
 }
 
+// WriteSessionMessage sends a message to a single connected client
+func (t *SSETransport) WriteSessionMessage(sessionID string, msg *transport.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	client, exists := t.clients[sessionID]
+	t.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	select {
+	case client.events <- data:
+	default:
+		// Client buffer full, skip
+	}
+	return nil
+}
+
+// OnSessionClosed registers a callback that is invoked when a client disconnects
+func (t *SSETransport) OnSessionClosed(fn func(sessionID string)) {
+	t.mu.Lock()
+	t.onClose = append(t.onClose, fn)
+	t.mu.Unlock()
+}
+
 // Close gracefully shuts down the HTTP server
 func (t *SSETransport) Close() error {
 	if t.server != nil {
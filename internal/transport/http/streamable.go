@@ -0,0 +1,617 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zmcp/odata-mcp/internal/constants"
+	"github.com/zmcp/odata-mcp/internal/transport"
+)
+
+// Streamable HTTP transport headers and defaults
+const (
+	SessionIDHeader       = "Mcp-Session-Id"
+	ProtocolVersionHeader = "Mcp-Protocol-Version"
+	DefaultMCPEndpoint    = "/mcp"
+
+	DefaultSessionIdleTimeout = 30 * time.Minute // Sessions without requests for this long expire
+	DefaultMaxSessions        = 1000             // Initialize is refused while this many sessions are open
+)
+
+// errTooManySessions is returned when the session limit is reached
+var errTooManySessions = errors.New("too many open sessions")
+
+// StreamableHTTPTransport implements the MCP Streamable HTTP transport:
+// a single endpoint that accepts JSON-RPC messages via POST, streams
+// server-initiated messages via GET and terminates sessions via DELETE.
+type StreamableHTTPTransport struct {
+	addr     string
+	endpoint string
+	server   *http.Server
+	handler  transport.Handler
+	sessions map[string]*streamableSession
+	onClose  []func(sessionID string)
+	auth     passThroughAuth
+	origins  []string
+	idle     time.Duration
+	max      int
+	mu       sync.RWMutex
+}
+
+type streamableSession struct {
+	id        string
+	events    chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	lastSeen  time.Time // Guarded by the transport mutex
+	streams   int       // Open GET streams, which keep the session alive
+}
+
+// NewStreamableHTTP creates a new Streamable HTTP transport
+func NewStreamableHTTP(addr string, handler transport.Handler) *StreamableHTTPTransport {
+	return &StreamableHTTPTransport{
+		addr:     addr,
+		endpoint: DefaultMCPEndpoint,
+		handler:  handler,
+		sessions: make(map[string]*streamableSession),
+		idle:     DefaultSessionIdleTimeout,
+		max:      DefaultMaxSessions,
+	}
+}
+
+// SetSessionLimits sets how long a session may stay idle and how many sessions
+// may be open at once. Zero keeps the default.
+func (t *StreamableHTTPTransport) SetSessionLimits(idleTimeout time.Duration, maxSessions int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if idleTimeout > 0 {
+		t.idle = idleTimeout
+	}
+	if maxSessions > 0 {
+		t.max = maxSessions
+	}
+}
+
+// SetAllowedOrigins allows browser requests from the given origins, e.g.
+// https://app.example.com, in addition to localhost origins
+func (t *StreamableHTTPTransport) SetAllowedOrigins(origins []string) {
+	t.origins = origins
+}
+
+// SetPassThroughAuth makes every POST forward the caller's Authorization header
+// and the named cookies to the OData service. Requests without them are rejected.
+func (t *StreamableHTTPTransport) SetPassThroughAuth(cookieNames []string) {
+	t.auth = passThroughAuth{enabled: true, cookies: cookieNames}
+}
+
+// Start initializes the HTTP server and begins listening
+func (t *StreamableHTTPTransport) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+
+	// Single MCP endpoint for POST, GET and DELETE
+	mux.Handle(t.endpoint, t)
+
+	// Health check endpoint
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	})
+
+	t.server = &http.Server{
+		Addr:    t.addr,
+		Handler: mux,
+	}
+
+	// Start server
+	go func() {
+		if err := t.server.ListenAndServe(); err != http.ErrServerClosed {
+			fmt.Printf("HTTP server error: %v\n", err)
+		}
+	}()
+
+	<-ctx.Done()
+	return t.Close()
+}
+
+// ServeHTTP dispatches requests to the MCP endpoint by method
+func (t *StreamableHTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browsers send an Origin header; rejecting foreign origins prevents DNS
+	// rebinding attacks against a server listening on localhost
+	if !t.allowedOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		t.handlePost(w, r)
+	case http.MethodGet:
+		t.handleGet(w, r)
+	case http.MethodDelete:
+		t.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost processes one JSON-RPC message or a batch of messages
+func (t *StreamableHTTPTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Check end-user credentials before a session is created for the caller
+	ctx, ok := t.auth.withCredentials(r.Context(), w, r)
+	if !ok {
+		return
+	}
+
+	messages, isBatch, err := decodeMessages(body)
+	if err != nil {
+		writeJSONRPCError(w, http.StatusBadRequest, -32700, "Parse error: "+err.Error())
+		return
+	}
+
+	// Initialize starts a new session, every other message must belong to one
+	var session *streamableSession
+	if containsMethod(messages, "initialize") {
+		session, err = t.createSession()
+		if errors.Is(err, errTooManySessions) {
+			writeJSONRPCError(w, http.StatusServiceUnavailable, -32603, err.Error())
+			return
+		}
+		if err != nil {
+			writeJSONRPCError(w, http.StatusInternalServerError, -32603, err.Error())
+			return
+		}
+		w.Header().Set(SessionIDHeader, session.id)
+	} else {
+		if !supportedProtocolVersion(r) {
+			writeJSONRPCError(w, http.StatusBadRequest, -32600, "Bad Request: unsupported "+ProtocolVersionHeader+" "+r.Header.Get(ProtocolVersionHeader))
+			return
+		}
+		var status int
+		session, status = t.lookupSession(r)
+		if session == nil {
+			writeJSONRPCError(w, status, -32600, http.StatusText(status)+": invalid or missing "+SessionIDHeader)
+			return
+		}
+	}
+
+	ctx = transport.WithSessionID(ctx, session.id)
+
+	// Notifications and responses only need to be acknowledged
+	hasRequests := false
+	for _, msg := range messages {
+		if msg.Method != "" && len(msg.ID) > 0 {
+			hasRequests = true
+			break
+		}
+	}
+
+	if !hasRequests {
+		for _, msg := range messages {
+			if msg.Method != "" && t.handler != nil {
+				t.handler(ctx, msg)
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if acceptsEventStream(r) {
+		t.streamResponses(ctx, w, messages)
+		return
+	}
+
+	responses := make([]*transport.Message, 0, len(messages))
+	for _, msg := range messages {
+		if response := t.process(ctx, msg); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if isBatch {
+		json.NewEncoder(w).Encode(responses)
+	} else if len(responses) > 0 {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// streamResponses answers a POST with an SSE stream that closes after the last response
+func (t *StreamableHTTPTransport) streamResponses(ctx context.Context, w http.ResponseWriter, messages []*transport.Message) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for _, msg := range messages {
+		response := t.process(ctx, msg)
+		if response == nil {
+			continue
+		}
+		if data, err := json.Marshal(response); err == nil {
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// handleGet opens an SSE stream for server-initiated messages of a session
+func (t *StreamableHTTPTransport) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !supportedProtocolVersion(r) {
+		http.Error(w, "Bad Request: unsupported "+ProtocolVersionHeader, http.StatusBadRequest)
+		return
+	}
+	session, status := t.lookupSession(r)
+	if session == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	t.mu.Lock()
+	session.streams++
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		session.streams--
+		session.lastSeen = time.Now()
+		t.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case event := <-session.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", event)
+			flusher.Flush()
+		case <-session.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleDelete terminates a session
+func (t *StreamableHTTPTransport) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !supportedProtocolVersion(r) {
+		http.Error(w, "Bad Request: unsupported "+ProtocolVersionHeader, http.StatusBadRequest)
+		return
+	}
+	session, status := t.lookupSession(r)
+	if session == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	t.closeSession(session.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// process runs a single message through the handler and returns its response
+func (t *StreamableHTTPTransport) process(ctx context.Context, msg *transport.Message) *transport.Message {
+	if msg.Method == "" || t.handler == nil {
+		return nil
+	}
+
+	response, err := t.handler(ctx, msg)
+	if err != nil {
+		return &transport.Message{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Error: &transport.Error{
+				Code:    -32603,
+				Message: err.Error(),
+			},
+		}
+	}
+	return response
+}
+
+// createSession registers a new session with a cryptographically random ID.
+// Idle sessions are expired first, and no session is created at the limit.
+func (t *StreamableHTTPTransport) createSession() (*streamableSession, error) {
+	t.expireSessions()
+
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &streamableSession{
+		id:       id,
+		events:   make(chan []byte, 100),
+		done:     make(chan struct{}),
+		lastSeen: time.Now(),
+	}
+
+	t.mu.Lock()
+	if len(t.sessions) >= t.max {
+		t.mu.Unlock()
+		return nil, errTooManySessions
+	}
+	t.sessions[session.id] = session
+	t.mu.Unlock()
+
+	return session, nil
+}
+
+// lookupSession returns the session of a request, or the HTTP status to reply
+// with, and marks it as active. Expired sessions are not found.
+func (t *StreamableHTTPTransport) lookupSession(r *http.Request) (*streamableSession, int) {
+	id := r.Header.Get(SessionIDHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	session, exists := t.sessions[id]
+	expired := exists && t.isExpired(session, now)
+	if exists && !expired {
+		session.lastSeen = now
+	}
+	t.mu.Unlock()
+
+	if expired {
+		t.closeSession(id)
+	}
+	if !exists || expired {
+		return nil, http.StatusNotFound
+	}
+	return session, http.StatusOK
+}
+
+// isExpired reports whether a session has been idle too long. The caller holds the mutex.
+func (t *StreamableHTTPTransport) isExpired(session *streamableSession, now time.Time) bool {
+	return session.streams == 0 && now.Sub(session.lastSeen) > t.idle
+}
+
+// expireSessions closes the sessions that have been idle too long
+func (t *StreamableHTTPTransport) expireSessions() {
+	now := time.Now()
+	var expired []string
+	t.mu.RLock()
+	for id, session := range t.sessions {
+		if t.isExpired(session, now) {
+			expired = append(expired, id)
+		}
+	}
+	t.mu.RUnlock()
+
+	for _, id := range expired {
+		t.closeSession(id)
+	}
+}
+
+// allowedOrigin reports whether a request with the given Origin header may use
+// the endpoint. Requests without Origin come from non-browser clients.
+func (t *StreamableHTTPTransport) allowedOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range t.origins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// closeSession removes a session and ends its GET stream
+func (t *StreamableHTTPTransport) closeSession(id string) {
+	t.mu.Lock()
+	session, exists := t.sessions[id]
+	delete(t.sessions, id)
+	t.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	session.closeOnce.Do(func() { close(session.done) })
+
+	t.mu.RLock()
+	callbacks := t.onClose
+	t.mu.RUnlock()
+	for _, fn := range callbacks {
+		fn(id)
+	}
+}
+
+// OnSessionClosed registers a callback that is invoked when a session ends
+func (t *StreamableHTTPTransport) OnSessionClosed(fn func(sessionID string)) {
+	t.mu.Lock()
+	t.onClose = append(t.onClose, fn)
+	t.mu.Unlock()
+}
+
+// ReadMessage is not used for Streamable HTTP transport
+func (t *StreamableHTTPTransport) ReadMessage() (*transport.Message, error) {
+	return nil, fmt.Errorf("ReadMessage not implemented for Streamable HTTP transport")
+}
+
+// WriteMessage sends a server-initiated message to the GET streams of all sessions
+func (t *StreamableHTTPTransport) WriteMessage(msg *transport.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, session := range t.sessions {
+		select {
+		case session.events <- data:
+		default:
+			// Session buffer full, skip
+		}
+	}
+
+	return nil
+}
+
+// WriteSessionMessage sends a server-initiated message to the GET stream of one session
+func (t *StreamableHTTPTransport) WriteSessionMessage(sessionID string, msg *transport.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	session, exists := t.sessions[sessionID]
+	t.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	select {
+	case session.events <- data:
+	default:
+		// Session buffer full, skip
+	}
+	return nil
+}
+
+// Close terminates all sessions and gracefully shuts down the HTTP server
+func (t *StreamableHTTPTransport) Close() error {
+	t.mu.RLock()
+	ids := make([]string, 0, len(t.sessions))
+	for id := range t.sessions {
+		ids = append(ids, id)
+	}
+	t.mu.RUnlock()
+
+	for _, id := range ids {
+		t.closeSession(id)
+	}
+
+	if t.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return t.server.Shutdown(ctx)
+	}
+	return nil
+}
+
+// newSessionID returns a cryptographically random session identifier
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// decodeMessages decodes a POST body holding a single message or a JSON array of messages
+func decodeMessages(body []byte) ([]*transport.Message, bool, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var messages []*transport.Message
+		if err := json.Unmarshal(trimmed, &messages); err != nil {
+			return nil, true, err
+		}
+		return messages, true, nil
+	}
+
+	var msg transport.Message
+	if err := json.Unmarshal(trimmed, &msg); err != nil {
+		return nil, false, err
+	}
+	return []*transport.Message{&msg}, false, nil
+}
+
+// containsMethod reports whether any message calls the given method
+func containsMethod(messages []*transport.Message, method string) bool {
+	for _, msg := range messages {
+		if msg.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
+// supportedProtocolVersion reports whether the Mcp-Protocol-Version header of a
+// request after initialize names a supported version. Clients that predate the
+// header send none, which is accepted.
+func supportedProtocolVersion(r *http.Request) bool {
+	version := r.Header.Get(ProtocolVersionHeader)
+	if version == "" {
+		return true
+	}
+	for _, supported := range constants.MCPSupportedProtocolVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEventStream reports whether the client accepts an SSE response
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// writeJSONRPCError writes a JSON-RPC error without an ID
+func writeJSONRPCError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&transport.Message{
+		JSONRPC: "2.0",
+		Error: &transport.Error{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
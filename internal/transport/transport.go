@@ -2,7 +2,10 @@ package transport
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"sort"
 )
 
 // Message represents a JSON-RPC message
@@ -38,4 +41,85 @@ type Transport interface {
 }
 
 // Handler processes incoming messages and returns responses
-type Handler func(ctx context.Context, msg *Message) (*Message, error)
\ No newline at end of file
+type Handler func(ctx context.Context, msg *Message) (*Message, error)
+
+// SessionTransport is implemented by transports that serve several client sessions
+type SessionTransport interface {
+	Transport
+
+	// WriteSessionMessage writes a message to a single session only
+	WriteSessionMessage(sessionID string, msg *Message) error
+
+	// OnSessionClosed registers a callback that is invoked when a session ends
+	OnSessionClosed(fn func(sessionID string))
+}
+
+type contextKey string
+
+const (
+	sessionIDKey   contextKey = "mcp-session-id"
+	credentialsKey contextKey = "end-user-credentials"
+	isolatedKey    contextKey = "isolated-request"
+)
+
+// Credentials holds the end-user credentials of an incoming HTTP request
+// that are passed through to the OData service
+type Credentials struct {
+	Authorization string            // Value of the Authorization header
+	Cookies       map[string]string // Selected cookies by name
+}
+
+// Fingerprint returns a stable hash identifying the credentials
+func (c *Credentials) Fingerprint() string {
+	names := make([]string, 0, len(c.Cookies))
+	for name := range c.Cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(c.Authorization))
+	for _, name := range names {
+		h.Write([]byte{0})
+		h.Write([]byte(name + "=" + c.Cookies[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WithSessionID returns a context carrying the session ID of the message being handled
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// SessionIDFromContext returns the session ID carried by ctx, or "" for session-less transports
+func SessionIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(sessionIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// WithIsolation returns a context marking a session-less request whose OData
+// session state (CSRF token, cookies) must not be shared with other callers
+func WithIsolation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, isolatedKey, true)
+}
+
+// IsolatedFromContext reports whether ctx belongs to a request marked by WithIsolation
+func IsolatedFromContext(ctx context.Context) bool {
+	isolated, _ := ctx.Value(isolatedKey).(bool)
+	return isolated
+}
+
+// WithCredentials returns a context carrying the end-user credentials of the request being handled
+func WithCredentials(ctx context.Context, creds *Credentials) context.Context {
+	return context.WithValue(ctx, credentialsKey, creds)
+}
+
+// CredentialsFromContext returns the end-user credentials carried by ctx, or nil if there are none
+func CredentialsFromContext(ctx context.Context) *Credentials {
+	if creds, ok := ctx.Value(credentialsKey).(*Credentials); ok {
+		return creds
+	}
+	return nil
+}